@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// followReader reads a file like tail -F: at the end of the file it waits for more data,
+// and it reopens the file when it is rotated or truncated.
+type followReader struct {
+	ctx  context.Context
+	path string
+	poll time.Duration
+
+	f      *os.File
+	offset int64
+}
+
+func newFollowReader(ctx context.Context, path string, poll time.Duration) *followReader {
+	return &followReader{ctx: ctx, path: path, poll: poll}
+}
+
+func (fr *followReader) Read(p []byte) (int, error) {
+	for {
+		if fr.f == nil {
+			f, err := os.Open(fr.path)
+			if err != nil && !os.IsNotExist(err) {
+				return 0, err
+			}
+			fr.f, fr.offset = f, 0
+		}
+		if fr.f != nil {
+			n, err := fr.f.Read(p)
+			fr.offset += int64(n)
+			if n > 0 {
+				return n, nil
+			}
+			if err != nil && err != io.EOF {
+				return 0, err
+			}
+			if fr.rotated() {
+				_ = fr.f.Close()
+				fr.f = nil
+				continue
+			}
+		}
+		select {
+		case <-fr.ctx.Done():
+			return 0, io.EOF
+		case <-time.After(fr.poll):
+		}
+	}
+}
+
+// rotated checks if the path refers to a different file now, or if the file was truncated.
+func (fr *followReader) rotated() bool {
+	pathInfo, err := os.Stat(fr.path)
+	if err != nil {
+		return false // keep the old file until a new one appears
+	}
+	info, err := fr.f.Stat()
+	if err != nil {
+		return true
+	}
+	return !os.SameFile(info, pathInfo) || pathInfo.Size() < fr.offset
+}
+
+func (fr *followReader) Close() error {
+	if fr.f == nil {
+		return nil
+	}
+	return fr.f.Close()
+}
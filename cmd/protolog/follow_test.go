@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFollowReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	write := func(flag int, text string) {
+		t.Helper()
+		f, err := os.OpenFile(path, flag|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if _, err := f.WriteString(text); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	fr := newFollowReader(ctx, path, time.Millisecond)
+	defer fr.Close()
+	lines := make(chan string)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sc := bufio.NewScanner(fr)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+	expect := func(want string) {
+		t.Helper()
+		select {
+		case got := <-lines:
+			if got != want {
+				t.Fatalf("expected line %q, got %q", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for line %q", want)
+		}
+	}
+
+	// the file does not exist yet
+	write(os.O_CREATE|os.O_EXCL, "first\n")
+	expect("first")
+	write(os.O_APPEND, "appended\n")
+	expect("appended")
+
+	// rotated: the remainder of the old file is read before the new file
+	write(os.O_APPEND, "last of old\n")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	write(os.O_CREATE|os.O_EXCL, "rotated\n")
+	expect("last of old")
+	expect("rotated")
+
+	// truncated: the file is read again from the start
+	write(os.O_TRUNC, "new\n")
+	expect("new")
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected follow reader to stop when the context is canceled")
+	}
+}
@@ -0,0 +1,231 @@
+// Command protolog pretty-prints, filters and converts log streams,
+// as produced by the proto-log LogfmtHandler, JSONHandler and TerminalHandler.
+//
+// Usage:
+//
+//	protolog [flags] [files...]
+//
+// Logs are read from stdin if no files are specified.
+// Follow mode (-f) only applies to files, and requires file arguments.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+type attrFlags []string
+
+func (a *attrFlags) String() string {
+	return strings.Join(*a, ",")
+}
+
+func (a *attrFlags) Set(v string) error {
+	*a = append(*a, v)
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "protolog:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := flag.NewFlagSet("protolog", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		inFormat  = fs.String("in", "auto", "input format: auto, logfmt, json or terminal")
+		outFormat = fs.String("out", "terminal", "output format: terminal, logfmt or json")
+		color     = fs.String("color", "auto", "color the terminal output: auto, always or never")
+		minLevel  = fs.String("level", "", "minimum level of records to show, e.g. warn")
+		msg       = fs.String("msg", "", "only show records with a message containing this substring")
+		query     = fs.String("q", "", "only show records matching the filter query, e.g. 'lvl>=warn && peer.id==abc'")
+		source    = fs.Bool("source", true, "show source info, if available")
+		noTime    = fs.Bool("no-time", false, "exclude the time from the output")
+		separator = fs.Bool("parse-separators", true, "parse numbers with _ thousand-separators")
+		follow    = fs.Bool("f", false, "follow the files, like tail -F, also when rotated (requires file arguments)")
+		poll      = fs.Duration("poll", 250*time.Millisecond, "poll interval of follow mode")
+		attrs     attrFlags
+	)
+	fs.Var(&attrs, "attr", "only show records with an attribute key=value, or key~substring (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *follow && fs.NArg() == 0 {
+		return errors.New("follow mode requires file arguments, stdin is always read until EOF")
+	}
+
+	var filters []log.LogFilter
+	if *minLevel != "" {
+		lvl, err := log.LevelFromString(*minLevel)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, log.MinLevelFilter(lvl))
+	}
+	if *msg != "" {
+		filters = append(filters, log.MessageContainsFilter(*msg))
+	}
+	if *query != "" {
+		f, err := log.ParseLogFilter(*query)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, f)
+	}
+	for _, a := range attrs {
+		if k, v, ok := strings.Cut(a, "~"); ok && !strings.Contains(k, "=") {
+			filters = append(filters, log.AttributesContainsFilter(k, v))
+		} else if k, v, ok := strings.Cut(a, "="); ok {
+			filters = append(filters, log.AttributesFilter(k, v))
+		} else {
+			return fmt.Errorf("invalid attribute filter %q, expected key=value or key~substring", a)
+		}
+	}
+
+	readerOpts := []log.ReaderOption{log.WithParseSeparators(*separator)}
+	switch *inFormat {
+	case "auto":
+		readerOpts = append(readerOpts, log.WithInputFormat(log.InputAuto))
+	case "logfmt":
+		readerOpts = append(readerOpts, log.WithInputFormat(log.InputLogfmt))
+	case "json":
+		readerOpts = append(readerOpts, log.WithInputFormat(log.InputJSON))
+	case "terminal":
+		readerOpts = append(readerOpts, log.WithInputFormat(log.InputTerminal))
+	default:
+		return fmt.Errorf("unknown input format %q", *inFormat)
+	}
+
+	formatOpts := []log.FormatOption{log.WithExcludeTime(*noTime)}
+	switch *color {
+	case "auto":
+		formatOpts = append(formatOpts, log.WithAutoColor())
+	case "always":
+		formatOpts = append(formatOpts, log.WithColor(true))
+	case "never":
+		formatOpts = append(formatOpts, log.WithColor(false))
+	default:
+		return fmt.Errorf("unknown color mode %q", *color)
+	}
+	var h slog.Handler
+	switch *outFormat {
+	case "terminal":
+		h = log.TerminalHandler(stdout, formatOpts...)
+	case "logfmt":
+		h = log.LogfmtHandler(stdout, formatOpts...)
+	case "json":
+		h = log.JSONHandler(stdout, formatOpts...)
+	default:
+		return fmt.Errorf("unknown output format %q", *outFormat)
+	}
+	p := &printer{handler: h, filters: filters, source: *source, stderr: stderr}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if fs.NArg() == 0 {
+		return p.print(ctx, log.NewReader(stdin, readerOpts...))
+	}
+	if !*follow {
+		for _, path := range fs.Args() {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			err = p.print(ctx, log.NewReader(f, readerOpts...))
+			_ = f.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, fs.NArg())
+	for i, path := range fs.Args() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fr := newFollowReader(ctx, path, *poll)
+			defer fr.Close()
+			errs[i] = p.print(ctx, log.NewReader(fr, readerOpts...))
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// printer filters and re-renders the records of readers.
+type printer struct {
+	handler slog.Handler
+	filters []log.LogFilter
+	source  bool
+	stderr  io.Writer
+}
+
+func (p *printer) print(ctx context.Context, r *log.Reader) error {
+	for {
+		rec, err := r.Next()
+		if errors.Is(err, io.EOF) || ctx.Err() != nil {
+			return nil
+		}
+		var parseErr *log.ParseError
+		if errors.As(err, &parseErr) {
+			fmt.Fprintln(p.stderr, "protolog: skipping", parseErr)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !p.match(&rec) {
+			continue
+		}
+		if err := p.handler.Handle(ctx, p.render(rec)); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *printer) match(rec *slog.Record) bool {
+	captured := &log.CapturedRecord{Record: rec}
+	for _, f := range p.filters {
+		if !f(captured) {
+			return false
+		}
+	}
+	return true
+}
+
+// render converts the source attribute of a parsed record into a file:line string,
+// since records that are read back do not have a program counter for the handlers to resolve.
+func (p *printer) render(rec slog.Record) slog.Record {
+	out := slog.NewRecord(rec.Time, rec.Level, rec.Message, 0)
+	rec.Attrs(func(a slog.Attr) bool {
+		if src, ok := a.Value.Any().(*slog.Source); ok && a.Key == slog.SourceKey {
+			if p.source {
+				out.AddAttrs(slog.String(slog.SourceKey, fmt.Sprintf("%s:%d", src.File, src.Line)))
+			}
+			return true
+		}
+		out.AddAttrs(a)
+		return true
+	})
+	return out
+}
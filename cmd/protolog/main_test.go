@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testInput = `t=2024-01-02T03:04:05Z lvl=info msg="hello world" peer=abc n=1_000
+t=2024-01-02T03:04:06Z lvl=warn msg=careful peer=def
+not a log line
+{"t":"2024-01-02T03:04:07Z","lvl":"error","msg":"from json","peer":"abc"}
+`
+
+func TestRun(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		// expected output lines, exactly
+		want []string
+		// expected error substring, if any
+		err string
+	}{
+		{
+			name: "terminal",
+			args: []string{"-color", "never"},
+			want: []string{
+				"INFO  hello world                              peer=abc n=1000",
+				"WARN  careful                                  peer=def",
+				"ERROR from json                                peer=abc",
+			},
+		},
+		{
+			name: "logfmt",
+			args: []string{"-out", "logfmt"},
+			want: []string{
+				`lvl=info msg="hello world" peer=abc n=1000`,
+				`lvl=warn msg=careful peer=def`,
+				`lvl=error msg="from json" peer=abc`,
+			},
+		},
+		{
+			name: "json",
+			args: []string{"-out", "json", "-level", "warn"},
+			want: []string{
+				`{"lvl":"warn","msg":"careful","peer":"def"}`,
+				`{"lvl":"error","msg":"from json","peer":"abc"}`,
+			},
+		},
+		{
+			name: "no separators",
+			args: []string{"-out", "logfmt", "-parse-separators=false", "-msg", "hello"},
+			want: []string{`lvl=info msg="hello world" peer=abc n=1_000`},
+		},
+		{
+			name: "query",
+			args: []string{"-out", "logfmt", "-q", "lvl>=warn && peer==abc"},
+			want: []string{`lvl=error msg="from json" peer=abc`},
+		},
+		{
+			name: "attrs",
+			args: []string{"-out", "logfmt", "-attr", "peer~de", "-attr", "peer=def"},
+			want: []string{`lvl=warn msg=careful peer=def`},
+		},
+		{
+			name: "logfmt input",
+			args: []string{"-out", "logfmt", "-in", "logfmt"},
+			want: []string{
+				`lvl=info msg="hello world" peer=abc n=1000`,
+				`lvl=warn msg=careful peer=def`,
+			},
+		},
+		{name: "follow stdin", args: []string{"-f"}, err: "follow mode requires file arguments"},
+		{name: "bad level", args: []string{"-level", "loud"}, err: "loud"},
+		{name: "bad query", args: []string{"-q", "lvl>="}, err: "invalid filter query"},
+		{name: "bad attr", args: []string{"-attr", "peer"}, err: "invalid attribute filter"},
+		{name: "bad input", args: []string{"-in", "xml"}, err: "unknown input format"},
+		{name: "bad output", args: []string{"-out", "xml"}, err: "unknown output format"},
+		{name: "bad color", args: []string{"-color", "rainbow"}, err: "unknown color mode"},
+		{name: "missing file", args: []string{"does-not-exist.log"}, err: "does-not-exist.log"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"-no-time"}, c.args...)
+			err := run(args, strings.NewReader(testInput), &stdout, &stderr)
+			if c.err != "" {
+				if err == nil || !strings.Contains(err.Error(), c.err) {
+					t.Fatalf("expected error containing %q, got %v", c.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+			if strings.Join(got, "\n") != strings.Join(c.want, "\n") {
+				t.Fatalf("unexpected output:\n%s\nexpected:\n%s", stdout.String(), strings.Join(c.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestRunSkipsInvalidLines(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"-in", "logfmt", "-out", "logfmt", "-no-time"}, strings.NewReader(testInput), &stdout, &stderr); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stderr.String(), "protolog: skipping") {
+		t.Fatalf("expected skipped lines to be reported, got %q", stderr.String())
+	}
+}
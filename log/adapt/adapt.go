@@ -0,0 +1,114 @@
+// Package adapt wraps a log.Logger in the logging APIs that other libraries expect.
+//
+// The adapters do not import the libraries, to keep this module free of dependencies.
+// The gRPC LoggerV2 and DepthLoggerV2, the libp2p go-log StandardLogger,
+// and zap SugaredLogger-style APIs only use builtin types, and are satisfied structurally.
+//
+// Other APIs refer to types of their own package, and need a thin wrapper in the importing module:
+//   - go-ethereum: the geth log.Logger is built on slog, with the same levels,
+//     so log.NewLogger(logger.Handler()) of the geth log package is the adapter.
+//   - logr: wrap a LogrSink in a logr.LogSink, see LogrSink.
+//   - hclog: wrap an HCLogger in a hclog.Logger, see HCLogger.
+//
+// All adapters keep the source location of the log call.
+// Wrappers that add a frame between the caller and the adapter use WithCallDepth to skip it.
+// The wrapped Logger determines the source, so its WithCallerSkip and Helper functions apply too.
+// Messages of print-style and printf-style methods are only formatted if the level is enabled.
+package adapt
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+// base is embedded by the adapters, to log records at the right call depth.
+type base struct {
+	logger log.Logger
+	depth  int
+	// caller is the logger with the frames of the adapter skipped,
+	// on top of the caller skip of the logger itself.
+	caller log.Logger
+}
+
+func newBase(l log.Logger, depth int) base {
+	if l == nil {
+		panic("nil Logger")
+	}
+	// skip [base.log, the adapter method]
+	return base{logger: l, depth: depth, caller: l.WithCallerSkip(2 + depth)}
+}
+
+func (b *base) enabled(level slog.Level) bool {
+	return b.logger.Enabled(context.Background(), level)
+}
+
+// callerLogger returns the logger that attributes records to the caller of the adapter method, plus extra frames.
+func (b *base) callerLogger(extra int) log.Logger {
+	if extra == 0 {
+		return b.caller
+	}
+	return b.caller.WithCallerSkip(extra)
+}
+
+// log emits a record with the source of the caller of the adapter method, plus b.depth and extra frames.
+// The logger determines the source, so its own caller skip and Helper functions are respected.
+// It must always be called directly by an exported adapter method, because it uses a fixed call depth.
+func (b *base) log(extra int, level slog.Level, msg string, args ...any) {
+	if !b.enabled(level) {
+		return
+	}
+	b.callerLogger(extra).Log(context.Background(), level, msg, args...)
+}
+
+// formatFunc formats the message of a printf-style or print-style log call.
+type formatFunc func(format string, args []any) string
+
+func sprint(_ string, args []any) string {
+	return fmt.Sprint(args...)
+}
+
+func sprintf(format string, args []any) string {
+	return fmt.Sprintf(format, args...)
+}
+
+func sprintln(_ string, args []any) string {
+	return strings.TrimSuffix(fmt.Sprintln(args...), "\n")
+}
+
+// logf is like log, but formats the message with fn, only if the level is enabled.
+// It must always be called directly by an exported adapter method, because it uses a fixed call depth.
+func (b *base) logf(extra int, level slog.Level, fn formatFunc, format string, args []any) {
+	if !b.enabled(level) {
+		return
+	}
+	b.callerLogger(extra).Log(context.Background(), level, fn(format, args))
+}
+
+// osExit is replaced in tests
+var osExit = os.Exit
+
+// exit flushes the handlers of the logger, see log.FlushHandlers, and then exits the process with exit code 1,
+// to follow up the crit-level record of a Fatal log call.
+// If the handler stack has a crit policy that exits or panics, that policy runs first, during the log call.
+func (b *base) exit() {
+	_ = log.FlushHandlers(b.logger.Handler())
+	osExit(1)
+}
+
+// VerbosityLevel converts a verbosity, like the V-levels of logr and gRPC, to a log level.
+// Verbosity 0 (and below) is LevelInfo, 1 is LevelDebug, and 2 and above is LevelTrace.
+func VerbosityLevel(v int) slog.Level {
+	switch {
+	case v <= 0:
+		return log.LevelInfo
+	case v == 1:
+		return log.LevelDebug
+	default:
+		return log.LevelTrace
+	}
+}
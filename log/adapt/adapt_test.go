@@ -0,0 +1,223 @@
+package adapt_test
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"runtime"
+	"testing"
+
+	"github.com/protolambda/proto-log/log"
+	"github.com/protolambda/proto-log/log/adapt"
+)
+
+// grpcLoggerV2 is a copy of the grpclog.LoggerV2 and grpclog.DepthLoggerV2 interfaces.
+type grpcLoggerV2 interface {
+	Info(args ...any)
+	Infoln(args ...any)
+	Infof(format string, args ...any)
+	Warning(args ...any)
+	Warningln(args ...any)
+	Warningf(format string, args ...any)
+	Error(args ...any)
+	Errorln(args ...any)
+	Errorf(format string, args ...any)
+	Fatal(args ...any)
+	Fatalln(args ...any)
+	Fatalf(format string, args ...any)
+	V(l int) bool
+	InfoDepth(depth int, args ...any)
+	WarningDepth(depth int, args ...any)
+	ErrorDepth(depth int, args ...any)
+	FatalDepth(depth int, args ...any)
+}
+
+// standardLogger is a copy of the go-log StandardLogger interface.
+type standardLogger interface {
+	Debug(args ...any)
+	Debugf(format string, args ...any)
+	Error(args ...any)
+	Errorf(format string, args ...any)
+	Fatal(args ...any)
+	Fatalf(format string, args ...any)
+	Info(args ...any)
+	Infof(format string, args ...any)
+	Panic(args ...any)
+	Panicf(format string, args ...any)
+	Warn(args ...any)
+	Warnf(format string, args ...any)
+}
+
+var (
+	_ grpcLoggerV2   = (*adapt.GRPCLogger)(nil)
+	_ standardLogger = (*adapt.StandardLogger)(nil)
+)
+
+func capturing(lvl slog.Level) (log.Logger, *log.CapturingHandler) {
+	logger := log.New(log.JSONHandler(io.Discard), log.CapturingMod(), log.LevelMod(lvl))
+	c, _ := log.FindHandler[*log.CapturingHandler](logger.Handler())
+	return logger, c
+}
+
+// checkRecord checks the level and message of the last record, and that its source is the caller of checkRecord.
+func checkRecord(t *testing.T, c *log.CapturingHandler, lvl slog.Level, msg string) *log.CapturedRecord {
+	t.Helper()
+	logs := *c.Logs
+	if len(logs) == 0 {
+		t.Fatal("expected a record")
+	}
+	rec := logs[len(logs)-1]
+	if rec.Level != lvl || rec.Message != msg {
+		t.Fatalf("expected %s %q, got %s %q", lvl, msg, rec.Level, rec.Message)
+	}
+	pc, _, _, _ := runtime.Caller(1)
+	want := runtime.FuncForPC(pc).Name()
+	got, _ := runtime.CallersFrames([]uintptr{rec.PC}).Next()
+	if got.Function != want {
+		t.Fatalf("expected source in %s, got %s", want, got.Function)
+	}
+	return rec
+}
+
+func TestGRPC(t *testing.T) {
+	logger, c := capturing(log.LevelDebug)
+	g := adapt.GRPC(logger)
+	g.Infof("hello %d", 1)
+	checkRecord(t, c, log.LevelInfo, "hello 1")
+	g.Warningln("a", "b")
+	checkRecord(t, c, log.LevelWarn, "a b")
+	g.Fatal("fatal")
+	checkRecord(t, c, log.LevelCrit, "fatal")
+	grpcDepthHelper(g)
+	checkRecord(t, c, log.LevelError, "from helper")
+	if !g.V(1) || g.V(2) {
+		t.Fatal("expected verbosity 1 to be enabled, and 2 to be disabled")
+	}
+}
+
+func grpcDepthHelper(g *adapt.GRPCLogger) {
+	g.ErrorDepth(1, "from helper")
+}
+
+func TestLibp2p(t *testing.T) {
+	logger, c := capturing(log.LevelDebug)
+	s := adapt.Libp2p(logger)
+	s.Debugf("x=%d", 2)
+	checkRecord(t, c, log.LevelDebug, "x=2")
+	func() {
+		defer func() {
+			if recover() != "boom" {
+				t.Fatal("expected panic")
+			}
+		}()
+		s.Panic("boom")
+	}()
+	if rec := c.FindLog(log.MessageFilter("boom")); rec == nil || rec.Level != log.LevelCrit {
+		t.Fatal("expected crit log before panic")
+	}
+}
+
+func TestSugared(t *testing.T) {
+	logger, c := capturing(log.LevelTrace)
+	s := adapt.Sugar(logger).With("a", 1)
+	s.Infow("hello", "b", 2)
+	rec := checkRecord(t, c, log.LevelInfo, "hello")
+	if rec.AttrValue("a") != int64(1) || rec.AttrValue("b") != int64(2) {
+		t.Fatal("expected attributes")
+	}
+	s.Tracef("%s!", "trace")
+	checkRecord(t, c, log.LevelTrace, "trace!")
+}
+
+func TestLogr(t *testing.T) {
+	logger, c := capturing(log.LevelDebug)
+	s := adapt.Logr(logger).WithName("p2p").WithValues("a", 1).WithName("peer")
+	if !s.Enabled(1) || s.Enabled(2) {
+		t.Fatal("expected V(1) to be enabled, and V(2) to be disabled")
+	}
+	s.Info(1, "hello", "b", 2)
+	rec := checkRecord(t, c, log.LevelDebug, "hello")
+	if rec.AttrValue(adapt.NameKey) != "p2p/peer" || rec.AttrValue("a") != int64(1) {
+		t.Fatal("expected name and values")
+	}
+	s.Error(errors.New("oops"), "failed")
+	rec = checkRecord(t, c, log.LevelError, "failed")
+	if err, _ := rec.AttrValue(log.ErrKey).(error); err == nil || err.Error() != "oops" {
+		t.Fatal("expected error attribute")
+	}
+}
+
+// hcl is like the wrapper that an importing module adds, to implement hclog.Logger.
+type hcl struct{ h *adapt.HCLogger }
+
+func (w hcl) Warn(msg string, args ...any) { w.h.Warn(msg, args...) }
+
+func TestHCLog(t *testing.T) {
+	logger, c := capturing(log.LevelInfo)
+	h := adapt.HCLog(logger).Named("raft").With("a", 1).Named("fsm")
+	if h.Name() != "raft.fsm" || len(h.ImpliedArgs()) != 2 {
+		t.Fatal("expected name and implied args")
+	}
+	if h.IsDebug() || !h.IsInfo() {
+		t.Fatal("expected debug to be disabled, and info to be enabled")
+	}
+	hcl{h.WithCallDepth(1)}.Warn("hello")
+	rec := checkRecord(t, c, log.LevelWarn, "hello")
+	if rec.AttrValue(adapt.NameKey) != "raft.fsm" {
+		t.Fatal("expected name")
+	}
+	h.Log(adapt.HCLogLevel(5), "error")
+	checkRecord(t, c, log.LevelError, "error")
+	if h.ResetNamed("x").Name() != "x" {
+		t.Fatal("expected reset name")
+	}
+}
+
+// countingStringer counts how often it is formatted.
+type countingStringer struct{ n *int }
+
+func (s countingStringer) String() string {
+	*s.n++
+	return "x"
+}
+
+func TestDisabledNotFormatted(t *testing.T) {
+	logger, c := capturing(log.LevelWarn)
+	var n int
+	arg := countingStringer{&n}
+	adapt.GRPC(logger).Infof("%s", arg)
+	adapt.GRPC(logger).Infoln(arg)
+	adapt.Sugar(logger).Infof("%s", arg)
+	adapt.Sugar(logger).Info(arg)
+	adapt.Libp2p(logger).Infof("%s", arg)
+	adapt.Libp2p(logger).Info(arg)
+	if n != 0 || len(*c.Logs) != 0 {
+		t.Fatalf("expected disabled calls not to be formatted, got %d formats", n)
+	}
+	adapt.Libp2p(logger).Warnf("%s", arg)
+	checkRecord(t, c, log.LevelWarn, "x")
+}
+
+func sugarCallerSkipHelper(s *adapt.Sugared) {
+	s.Warn("skipped")
+}
+
+func sugarMarkedHelper(s *adapt.Sugared) {
+	log.Helper()
+	s.Warn("marked")
+}
+
+func TestCallerSkip(t *testing.T) {
+	logger, c := capturing(log.LevelInfo)
+	sugarCallerSkipHelper(adapt.Sugar(logger.WithCallerSkip(1)))
+	checkRecord(t, c, log.LevelWarn, "skipped")
+	sugarMarkedHelper(adapt.Sugar(logger))
+	checkRecord(t, c, log.LevelWarn, "marked")
+
+	logger = log.New(log.JSONHandler(io.Discard), log.CapturingMod(), log.SkipPCMod())
+	c, _ = log.FindHandler[*log.CapturingHandler](logger.Handler())
+	adapt.Sugar(logger).Info("no pc")
+	if rec := c.FindLog(); rec == nil || rec.PC != 0 {
+		t.Fatal("expected no program counter")
+	}
+}
@@ -0,0 +1,88 @@
+package adapt
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+// flushHandler records if it was flushed.
+type flushHandler struct {
+	slog.Handler
+	flushed *bool
+}
+
+func (h flushHandler) Unwrap() slog.Handler { return h.Handler }
+
+func (h flushHandler) Flush() error {
+	*h.flushed = true
+	return nil
+}
+
+func TestFatal(t *testing.T) {
+	exitCode := -1
+	osExit = func(code int) { exitCode = code }
+	defer func() { osExit = os.Exit }()
+
+	cases := []struct {
+		name  string
+		fatal func(l log.Logger)
+	}{
+		{"libp2p Fatal", func(l log.Logger) { Libp2p(l).Fatal("bye") }},
+		{"libp2p Fatalf", func(l log.Logger) { Libp2p(l).Fatalf("%s", "bye") }},
+		{"sugared Fatal", func(l log.Logger) { Sugar(l).Fatal("bye") }},
+		{"sugared Fatalf", func(l log.Logger) { Sugar(l).Fatalf("%s", "bye") }},
+		{"sugared Fatalw", func(l log.Logger) { Sugar(l).Fatalw("bye", "a", 1) }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			exitCode = -1
+			var flushed bool
+			logger := log.New(flushHandler{Handler: log.JSONHandler(io.Discard), flushed: &flushed}, log.CapturingMod())
+			capt, _ := log.FindHandler[*log.CapturingHandler](logger.Handler())
+			c.fatal(logger)
+			if rec := capt.FindLog(log.MessageFilter("bye")); rec == nil || rec.Level != log.LevelCrit {
+				t.Fatal("expected crit log before exit")
+			}
+			if !flushed {
+				t.Fatal("expected handlers to be flushed before exit")
+			}
+			if exitCode != 1 {
+				t.Fatalf("expected exit code 1, got %d", exitCode)
+			}
+		})
+	}
+}
+
+func TestPanic(t *testing.T) {
+	cases := []struct {
+		name  string
+		panic func(l log.Logger)
+	}{
+		{"libp2p Panic", func(l log.Logger) { Libp2p(l).Panic("boom") }},
+		{"libp2p Panicf", func(l log.Logger) { Libp2p(l).Panicf("%s", "boom") }},
+		{"sugared Panic", func(l log.Logger) { Sugar(l).Panic("boom") }},
+		{"sugared Panicf", func(l log.Logger) { Sugar(l).Panicf("%s", "boom") }},
+		{"sugared Panicw", func(l log.Logger) { Sugar(l).Panicw("boom", "a", 1) }},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			logger := log.New(log.JSONHandler(io.Discard), log.CapturingMod())
+			capt, _ := log.FindHandler[*log.CapturingHandler](logger.Handler())
+			func() {
+				defer func() {
+					if v := recover(); v != "boom" {
+						t.Fatalf("expected panic with message, got %v", v)
+					}
+				}()
+				c.panic(logger)
+			}()
+			if rec := capt.FindLog(log.MessageFilter("boom")); rec == nil || rec.Level != log.LevelCrit {
+				t.Fatal("expected crit log before panic")
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package adapt
+
+import (
+	"github.com/protolambda/proto-log/log"
+)
+
+// GRPCLogger implements the LoggerV2 and DepthLoggerV2 interfaces of google.golang.org/grpc/grpclog,
+// for use with grpclog.SetLoggerV2.
+//
+// Fatal logs are logged at LevelCrit. gRPC itself exits the process after a Fatal log.
+type GRPCLogger struct {
+	base
+}
+
+// GRPC adapts the logger to the gRPC LoggerV2 interface.
+func GRPC(l log.Logger) *GRPCLogger {
+	return &GRPCLogger{base: newBase(l, 0)}
+}
+
+// WithCallDepth returns a GRPCLogger that skips depth additional frames to find the source of the log call.
+func (g *GRPCLogger) WithCallDepth(depth int) *GRPCLogger {
+	c := *g
+	c.base = newBase(c.logger, c.depth+depth)
+	return &c
+}
+
+func (g *GRPCLogger) Info(args ...any) {
+	g.logf(0, log.LevelInfo, sprint, "", args)
+}
+
+func (g *GRPCLogger) Infoln(args ...any) {
+	g.logf(0, log.LevelInfo, sprintln, "", args)
+}
+
+func (g *GRPCLogger) Infof(format string, args ...any) {
+	g.logf(0, log.LevelInfo, sprintf, format, args)
+}
+
+func (g *GRPCLogger) Warning(args ...any) {
+	g.logf(0, log.LevelWarn, sprint, "", args)
+}
+
+func (g *GRPCLogger) Warningln(args ...any) {
+	g.logf(0, log.LevelWarn, sprintln, "", args)
+}
+
+func (g *GRPCLogger) Warningf(format string, args ...any) {
+	g.logf(0, log.LevelWarn, sprintf, format, args)
+}
+
+func (g *GRPCLogger) Error(args ...any) {
+	g.logf(0, log.LevelError, sprint, "", args)
+}
+
+func (g *GRPCLogger) Errorln(args ...any) {
+	g.logf(0, log.LevelError, sprintln, "", args)
+}
+
+func (g *GRPCLogger) Errorf(format string, args ...any) {
+	g.logf(0, log.LevelError, sprintf, format, args)
+}
+
+func (g *GRPCLogger) Fatal(args ...any) {
+	g.logf(0, log.LevelCrit, sprint, "", args)
+}
+
+func (g *GRPCLogger) Fatalln(args ...any) {
+	g.logf(0, log.LevelCrit, sprintln, "", args)
+}
+
+func (g *GRPCLogger) Fatalf(format string, args ...any) {
+	g.logf(0, log.LevelCrit, sprintf, format, args)
+}
+
+// V reports whether verbosity level l is enabled, see VerbosityLevel.
+func (g *GRPCLogger) V(l int) bool {
+	return g.enabled(VerbosityLevel(l))
+}
+
+// InfoDepth logs like Info, with the source of the caller depth frames above the caller of InfoDepth.
+func (g *GRPCLogger) InfoDepth(depth int, args ...any) {
+	g.logf(depth, log.LevelInfo, sprint, "", args)
+}
+
+// WarningDepth logs like Warning, with the source of the caller depth frames above the caller of WarningDepth.
+func (g *GRPCLogger) WarningDepth(depth int, args ...any) {
+	g.logf(depth, log.LevelWarn, sprint, "", args)
+}
+
+// ErrorDepth logs like Error, with the source of the caller depth frames above the caller of ErrorDepth.
+func (g *GRPCLogger) ErrorDepth(depth int, args ...any) {
+	g.logf(depth, log.LevelError, sprint, "", args)
+}
+
+// FatalDepth logs like Fatal, with the source of the caller depth frames above the caller of FatalDepth.
+func (g *GRPCLogger) FatalDepth(depth int, args ...any) {
+	g.logf(depth, log.LevelCrit, sprint, "", args)
+}
@@ -0,0 +1,130 @@
+package adapt
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+// HCLogLevel converts a github.com/hashicorp/go-hclog Level to a log level.
+// NoLevel is converted to LevelInfo, and Off to a level above LevelCrit.
+func HCLogLevel(level int) slog.Level {
+	switch level {
+	case 1: // hclog.Trace
+		return log.LevelTrace
+	case 2: // hclog.Debug
+		return log.LevelDebug
+	case 4: // hclog.Warn
+		return log.LevelWarn
+	case 5: // hclog.Error
+		return log.LevelError
+	case 6: // hclog.Off
+		return log.LevelCrit + 1
+	default: // hclog.NoLevel and hclog.Info
+		return log.LevelInfo
+	}
+}
+
+// HCLogger implements the methods of the Logger interface of github.com/hashicorp/go-hclog,
+// that do not refer to hclog types. A wrapper in the importing module adds the other methods,
+// converting levels with HCLogLevel, and wrapping the results of With, Named and ResetNamed.
+// The wrapper passes its own frame to WithCallDepth:
+//
+//	type hcl struct{ h *adapt.HCLogger }
+//
+//	func (w hcl) Info(msg string, args ...any) { w.h.Info(msg, args...) }
+//	func (w hcl) With(args ...any) hclog.Logger { return hcl{w.h.With(args...)} }
+//	// ...
+//
+//	logger := hcl{adapt.HCLog(l).WithCallDepth(1)}
+//
+// Names are joined with "." into the NameKey attribute.
+type HCLogger struct {
+	base
+	values  log.Logger // the logger with the implied args, without the name
+	implied []any
+	name    string
+}
+
+// HCLog adapts the logger to the hclog Logger interface.
+func HCLog(l log.Logger) *HCLogger {
+	return &HCLogger{base: newBase(l, 0), values: l}
+}
+
+// WithCallDepth returns an HCLogger that skips depth additional frames to find the source of the log call.
+func (h *HCLogger) WithCallDepth(depth int) *HCLogger {
+	c := *h
+	c.base = newBase(c.logger, c.depth+depth)
+	return &c
+}
+
+// Log logs a message at the level, with key/value pairs.
+func (h *HCLogger) Log(level slog.Level, msg string, args ...any) {
+	h.log(0, level, msg, args...)
+}
+
+func (h *HCLogger) Trace(msg string, args ...any) {
+	h.log(0, log.LevelTrace, msg, args...)
+}
+
+func (h *HCLogger) Debug(msg string, args ...any) {
+	h.log(0, log.LevelDebug, msg, args...)
+}
+
+func (h *HCLogger) Info(msg string, args ...any) {
+	h.log(0, log.LevelInfo, msg, args...)
+}
+
+func (h *HCLogger) Warn(msg string, args ...any) {
+	h.log(0, log.LevelWarn, msg, args...)
+}
+
+func (h *HCLogger) Error(msg string, args ...any) {
+	h.log(0, log.LevelError, msg, args...)
+}
+
+func (h *HCLogger) IsTrace() bool { return h.enabled(log.LevelTrace) }
+
+func (h *HCLogger) IsDebug() bool { return h.enabled(log.LevelDebug) }
+
+func (h *HCLogger) IsInfo() bool { return h.enabled(log.LevelInfo) }
+
+func (h *HCLogger) IsWarn() bool { return h.enabled(log.LevelWarn) }
+
+func (h *HCLogger) IsError() bool { return h.enabled(log.LevelError) }
+
+// ImpliedArgs returns the key/value pairs that were added with With.
+func (h *HCLogger) ImpliedArgs() []any {
+	return h.implied
+}
+
+// Name returns the logger name.
+func (h *HCLogger) Name() string {
+	return h.name
+}
+
+// With returns an HCLogger that includes the key/value pairs as attributes.
+func (h *HCLogger) With(args ...any) *HCLogger {
+	c := *h
+	c.implied = append(slices.Clip(h.implied), args...)
+	c.values = h.values.With(args...)
+	c.base = newBase(withName(c.values, c.name), c.depth)
+	return &c
+}
+
+// Named returns an HCLogger with the name appended to the logger name.
+func (h *HCLogger) Named(name string) *HCLogger {
+	if h.name != "" {
+		name = h.name + "." + name
+	}
+	return h.ResetNamed(name)
+}
+
+// ResetNamed returns an HCLogger with the name as logger name.
+func (h *HCLogger) ResetNamed(name string) *HCLogger {
+	c := *h
+	c.name = name
+	c.base = newBase(withName(c.values, name), c.depth)
+	return &c
+}
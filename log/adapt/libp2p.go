@@ -0,0 +1,82 @@
+package adapt
+
+import (
+	"fmt"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+// StandardLogger implements the StandardLogger interface of github.com/ipfs/go-log,
+// as accepted by libp2p components.
+//
+// Fatal logs at LevelCrit and then flushes the handlers and exits the process with os.Exit(1),
+// and Panic logs at LevelCrit and then panics with the message, like the go-log implementation.
+type StandardLogger struct {
+	base
+}
+
+// Libp2p adapts the logger to the go-log StandardLogger interface.
+func Libp2p(l log.Logger) *StandardLogger {
+	return &StandardLogger{base: newBase(l, 0)}
+}
+
+// WithCallDepth returns a StandardLogger that skips depth additional frames to find the source of the log call.
+func (s *StandardLogger) WithCallDepth(depth int) *StandardLogger {
+	c := *s
+	c.base = newBase(c.logger, c.depth+depth)
+	return &c
+}
+
+func (s *StandardLogger) Debug(args ...any) {
+	s.logf(0, log.LevelDebug, sprint, "", args)
+}
+
+func (s *StandardLogger) Debugf(format string, args ...any) {
+	s.logf(0, log.LevelDebug, sprintf, format, args)
+}
+
+func (s *StandardLogger) Info(args ...any) {
+	s.logf(0, log.LevelInfo, sprint, "", args)
+}
+
+func (s *StandardLogger) Infof(format string, args ...any) {
+	s.logf(0, log.LevelInfo, sprintf, format, args)
+}
+
+func (s *StandardLogger) Warn(args ...any) {
+	s.logf(0, log.LevelWarn, sprint, "", args)
+}
+
+func (s *StandardLogger) Warnf(format string, args ...any) {
+	s.logf(0, log.LevelWarn, sprintf, format, args)
+}
+
+func (s *StandardLogger) Error(args ...any) {
+	s.logf(0, log.LevelError, sprint, "", args)
+}
+
+func (s *StandardLogger) Errorf(format string, args ...any) {
+	s.logf(0, log.LevelError, sprintf, format, args)
+}
+
+func (s *StandardLogger) Fatal(args ...any) {
+	s.logf(0, log.LevelCrit, sprint, "", args)
+	s.exit()
+}
+
+func (s *StandardLogger) Fatalf(format string, args ...any) {
+	s.logf(0, log.LevelCrit, sprintf, format, args)
+	s.exit()
+}
+
+func (s *StandardLogger) Panic(args ...any) {
+	msg := fmt.Sprint(args...)
+	s.log(0, log.LevelCrit, msg)
+	panic(msg)
+}
+
+func (s *StandardLogger) Panicf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	s.log(0, log.LevelCrit, msg)
+	panic(msg)
+}
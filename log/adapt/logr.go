@@ -0,0 +1,87 @@
+package adapt
+
+import (
+	"github.com/protolambda/proto-log/log"
+)
+
+// NameKey is the attribute key of the logger name, of the logr and hclog adapters.
+const NameKey = "logger"
+
+// withName returns the logger with the name attribute, if the name is not empty.
+func withName(l log.Logger, name string) log.Logger {
+	if name == "" {
+		return l
+	}
+	return l.With(NameKey, name)
+}
+
+// LogrSink implements the methods of the LogSink interface of github.com/go-logr/logr,
+// except for the methods that refer to logr types, which a wrapper in the importing module adds:
+//
+//	type sink struct{ s *adapt.LogrSink }
+//
+//	func (w *sink) Init(info logr.RuntimeInfo) { w.s = w.s.WithCallDepth(info.CallDepth + 1) }
+//	func (w *sink) Enabled(level int) bool { return w.s.Enabled(level) }
+//	func (w *sink) Info(level int, msg string, kv ...any) { w.s.Info(level, msg, kv...) }
+//	func (w *sink) Error(err error, msg string, kv ...any) { w.s.Error(err, msg, kv...) }
+//	func (w *sink) WithValues(kv ...any) logr.LogSink { return &sink{w.s.WithValues(kv...)} }
+//	func (w *sink) WithName(name string) logr.LogSink { return &sink{w.s.WithName(name)} }
+//
+//	logger := logr.New(&sink{adapt.Logr(l)})
+//
+// The call depth of Init is increased by one, to skip the wrapper frame.
+// V-levels are converted with VerbosityLevel, and names are joined with "/" into the NameKey attribute.
+type LogrSink struct {
+	base
+	values log.Logger // the logger with the values, without the name
+	name   string
+}
+
+// Logr adapts the logger to the logr LogSink interface.
+func Logr(l log.Logger) *LogrSink {
+	return &LogrSink{base: newBase(l, 0), values: l}
+}
+
+// WithCallDepth returns a LogrSink that skips depth additional frames to find the source of the log call.
+func (s *LogrSink) WithCallDepth(depth int) *LogrSink {
+	c := *s
+	c.base = newBase(c.logger, c.depth+depth)
+	return &c
+}
+
+// Enabled reports whether logs of the V-level are enabled.
+func (s *LogrSink) Enabled(level int) bool {
+	return s.enabled(VerbosityLevel(level))
+}
+
+// Info logs a message at the V-level, with key/value pairs.
+func (s *LogrSink) Info(level int, msg string, keysAndValues ...any) {
+	s.log(0, VerbosityLevel(level), msg, keysAndValues...)
+}
+
+// Error logs the error at LevelError, with key/value pairs.
+func (s *LogrSink) Error(err error, msg string, keysAndValues ...any) {
+	if err != nil {
+		keysAndValues = append([]any{log.Err(err)}, keysAndValues...)
+	}
+	s.log(0, log.LevelError, msg, keysAndValues...)
+}
+
+// WithValues returns a LogrSink that includes the key/value pairs as attributes.
+func (s *LogrSink) WithValues(keysAndValues ...any) *LogrSink {
+	c := *s
+	c.values = s.values.With(keysAndValues...)
+	c.base = newBase(withName(c.values, c.name), c.depth)
+	return &c
+}
+
+// WithName returns a LogrSink with the name appended to the logger name.
+func (s *LogrSink) WithName(name string) *LogrSink {
+	c := *s
+	if c.name != "" {
+		c.name += "/"
+	}
+	c.name += name
+	c.base = newBase(withName(c.values, c.name), c.depth)
+	return &c
+}
@@ -0,0 +1,133 @@
+package adapt
+
+import (
+	"fmt"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+// Sugared implements the printf-style and key/value-style API of a zap SugaredLogger:
+// Info logs the arguments like fmt.Sprint, Infof like fmt.Sprintf,
+// and Infow logs a message with key/value pairs, that are kept as attributes.
+//
+// Fatal logs at LevelCrit and then flushes the handlers and exits the process with os.Exit(1),
+// and Panic logs at LevelCrit and then panics with the message, like zap.
+type Sugared struct {
+	base
+}
+
+// Sugar adapts the logger to the zap SugaredLogger API.
+func Sugar(l log.Logger) *Sugared {
+	return &Sugared{base: newBase(l, 0)}
+}
+
+// WithCallDepth returns a Sugared logger that skips depth additional frames to find the source of the log call.
+func (s *Sugared) WithCallDepth(depth int) *Sugared {
+	c := *s
+	c.base = newBase(c.logger, c.depth+depth)
+	return &c
+}
+
+// With returns a Sugared logger that includes the key/value pairs as attributes.
+func (s *Sugared) With(args ...any) *Sugared {
+	c := *s
+	c.base = newBase(s.logger.With(args...), s.depth)
+	return &c
+}
+
+// Desugar returns the wrapped logger.
+func (s *Sugared) Desugar() log.Logger {
+	return s.logger
+}
+
+func (s *Sugared) Trace(args ...any) {
+	s.logf(0, log.LevelTrace, sprint, "", args)
+}
+
+func (s *Sugared) Tracef(format string, args ...any) {
+	s.logf(0, log.LevelTrace, sprintf, format, args)
+}
+
+func (s *Sugared) Tracew(msg string, keysAndValues ...any) {
+	s.log(0, log.LevelTrace, msg, keysAndValues...)
+}
+
+func (s *Sugared) Debug(args ...any) {
+	s.logf(0, log.LevelDebug, sprint, "", args)
+}
+
+func (s *Sugared) Debugf(format string, args ...any) {
+	s.logf(0, log.LevelDebug, sprintf, format, args)
+}
+
+func (s *Sugared) Debugw(msg string, keysAndValues ...any) {
+	s.log(0, log.LevelDebug, msg, keysAndValues...)
+}
+
+func (s *Sugared) Info(args ...any) {
+	s.logf(0, log.LevelInfo, sprint, "", args)
+}
+
+func (s *Sugared) Infof(format string, args ...any) {
+	s.logf(0, log.LevelInfo, sprintf, format, args)
+}
+
+func (s *Sugared) Infow(msg string, keysAndValues ...any) {
+	s.log(0, log.LevelInfo, msg, keysAndValues...)
+}
+
+func (s *Sugared) Warn(args ...any) {
+	s.logf(0, log.LevelWarn, sprint, "", args)
+}
+
+func (s *Sugared) Warnf(format string, args ...any) {
+	s.logf(0, log.LevelWarn, sprintf, format, args)
+}
+
+func (s *Sugared) Warnw(msg string, keysAndValues ...any) {
+	s.log(0, log.LevelWarn, msg, keysAndValues...)
+}
+
+func (s *Sugared) Error(args ...any) {
+	s.logf(0, log.LevelError, sprint, "", args)
+}
+
+func (s *Sugared) Errorf(format string, args ...any) {
+	s.logf(0, log.LevelError, sprintf, format, args)
+}
+
+func (s *Sugared) Errorw(msg string, keysAndValues ...any) {
+	s.log(0, log.LevelError, msg, keysAndValues...)
+}
+
+func (s *Sugared) Panic(args ...any) {
+	msg := fmt.Sprint(args...)
+	s.log(0, log.LevelCrit, msg)
+	panic(msg)
+}
+
+func (s *Sugared) Panicf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	s.log(0, log.LevelCrit, msg)
+	panic(msg)
+}
+
+func (s *Sugared) Panicw(msg string, keysAndValues ...any) {
+	s.log(0, log.LevelCrit, msg, keysAndValues...)
+	panic(msg)
+}
+
+func (s *Sugared) Fatal(args ...any) {
+	s.logf(0, log.LevelCrit, sprint, "", args)
+	s.exit()
+}
+
+func (s *Sugared) Fatalf(format string, args ...any) {
+	s.logf(0, log.LevelCrit, sprintf, format, args)
+	s.exit()
+}
+
+func (s *Sugared) Fatalw(msg string, keysAndValues ...any) {
+	s.log(0, log.LevelCrit, msg, keysAndValues...)
+	s.exit()
+}
@@ -0,0 +1,91 @@
+package log
+
+import (
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"time"
+)
+
+// The typed attribute constructors below pair a key with a value of the expected type,
+// to avoid mismatched key/value arguments, which are only detected at runtime as "!BADKEY" attributes.
+
+// ErrKey is the attribute key used by Err.
+const ErrKey = "err"
+
+// Hex returns an attribute with the bytes as 0x-prefixed hex string.
+// The bytes are only encoded when the attribute is logged, and the TerminalHandler encodes them
+// without intermediate allocations. The bytes must not be modified after the log call.
+func Hex(key string, b []byte) slog.Attr {
+	return slog.Any(key, hexBytes(b))
+}
+
+// hexBytes is lazily formatted as 0x-prefixed hex string.
+type hexBytes []byte
+
+func (b hexBytes) appendHex(dst []byte) []byte {
+	dst = append(dst, "0x"...)
+	return hex.AppendEncode(dst, b)
+}
+
+func (b hexBytes) String() string {
+	var tmp [2 + 2*32]byte // hashes are encoded without intermediate allocation
+	return string(b.appendHex(tmp[:0]))
+}
+
+func (b hexBytes) LogValue() slog.Value {
+	return slog.StringValue(b.String())
+}
+
+// hexHash is like hexBytes, but holds a copy of the hash, so the caller may reuse its memory.
+type hexHash [32]byte
+
+func (h hexHash) String() string {
+	return hexBytes(h[:]).String()
+}
+
+func (h hexHash) LogValue() slog.Value {
+	return hexBytes(h[:]).LogValue()
+}
+
+// Hash returns an attribute with the 32-byte hash as 0x-prefixed hex string.
+// Like Hex, the hash is only encoded when the attribute is logged.
+func Hash[H ~[32]byte](key string, h H) slog.Attr {
+	return slog.Any(key, hexHash(h))
+}
+
+// Bytes returns an attribute with the raw bytes.
+// The terminal handler formats bytes as 0x-prefixed hex string if enabled with WithHexBytes,
+// the JSON handler as base64 string. Use Hex to always format bytes as hex string.
+func Bytes(key string, b []byte) slog.Attr {
+	return slog.Any(key, b)
+}
+
+// BigInt returns an attribute with the big integer.
+// Handlers format it as decimal number, or as "<nil>".
+func BigInt(key string, n *big.Int) slog.Attr {
+	return slog.Any(key, n)
+}
+
+// Err returns an attribute with the error, under the ErrKey.
+func Err(err error) slog.Attr {
+	return slog.Any(ErrKey, err)
+}
+
+// Dur returns an attribute with the duration.
+func Dur(key string, d time.Duration) slog.Attr {
+	return slog.Duration(key, d)
+}
+
+// Stringer returns an attribute with the value, formatted with its String method.
+// A nil value is formatted as "<nil>".
+func Stringer(key string, s fmt.Stringer) slog.Attr {
+	return slog.Any(key, s)
+}
+
+// Obj returns an attribute with the value, which handlers may format as nested object,
+// see WithNestedFormat.
+func Obj(key string, v any) slog.Attr {
+	return slog.Any(key, v)
+}
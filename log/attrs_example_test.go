@@ -0,0 +1,23 @@
+package log_test
+
+import (
+	"errors"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func ExampleHash() {
+	h := log.LogfmtHandler(os.Stdout, log.WithExcludeTime(true))
+	logger := log.New(h)
+	logger.Info("imported block",
+		log.Hash("hash", [32]byte{0xaa, 31: 0xff}),
+		log.BigInt("number", big.NewInt(1234567)),
+		log.Dur("elapsed", 1500*time.Millisecond),
+		log.Err(errors.New("no peers")),
+	)
+	// Output:
+	// lvl=info msg="imported block" hash=0xaa000000000000000000000000000000000000000000000000000000000000ff number=1234567 elapsed=1.5s err="no peers"
+}
@@ -0,0 +1,51 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func TestTypedAttrs(t *testing.T) {
+	assertEqual(t, log.Hex("h", []byte{0x01, 0xab}).Value.String(), "0x01ab")
+	assertEqual(t, log.Hash("h", [32]byte{31: 1}).Value.Resolve().Kind(), slog.KindString)
+	assertEqual(t, log.Dur("d", time.Second).Value.Kind(), slog.KindDuration)
+	assertEqual(t, log.Err(nil).Key, log.ErrKey)
+
+	var buf bytes.Buffer
+	logger := log.New(log.TerminalHandler(&buf, log.WithExcludeTime(true), log.WithHexBytes(true)))
+	var nilStringer *big.Int
+	logger.Info("typed",
+		log.Bytes("b", []byte{0xde, 0xad}),
+		log.BigInt("n", nil),
+		log.Stringer("s", nilStringer),
+		log.Err(errors.New("oops")),
+	)
+	got := buf.String()
+	assertSubstring(t, got, "b=0xdead ")
+	assertSubstring(t, got, "n=<nil> ")
+	assertSubstring(t, got, "s=<nil> ")
+	assertSubstring(t, got, "err=oops\n")
+
+	buf.Reset()
+	logger = log.New(log.TerminalHandler(&buf, log.WithExcludeTime(true)))
+	logger.Info("raw", log.Bytes("b", []byte{0xde, 0xad}))
+	assertSubstring(t, buf.String(), "b=\"[222 173]\"")
+}
+
+func TestHexAllocs(t *testing.T) {
+	logger := log.New(log.TerminalHandler(io.Discard))
+	b, n := make([]byte, 20), 42
+	attrAllocs := testing.AllocsPerRun(100, func() { logger.Info("attr", slog.Int("n", n)) })
+	// only the byte slice itself is boxed, the bytes are encoded into the output buffer
+	hexAllocs := testing.AllocsPerRun(100, func() { logger.Info("hex", log.Hex("b", b), log.Hash("h", [32]byte{})) })
+	if hexAllocs > 2*attrAllocs+2 {
+		t.Fatalf("expected hex attributes to be encoded lazily, got %v allocs, vs. %v for an int attribute", hexAllocs, attrAllocs)
+	}
+}
@@ -0,0 +1,192 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// The benchmarks compare the Logger against a raw slog.Logger, with equivalent handlers.
+// Third-party loggers like zap are not compared here, to keep the module free of dependencies.
+// Run with:
+//
+//	go test ./log -run '^$' -bench . -benchmem
+
+var benchHandlers = []struct {
+	name string
+	new  func(w io.Writer) slog.Handler
+}{
+	{"terminal", func(w io.Writer) slog.Handler { return TerminalHandler(w) }},
+	{"logfmt", func(w io.Writer) slog.Handler { return LogfmtHandler(w) }},
+	{"json", func(w io.Writer) slog.Handler { return JSONHandler(w) }},
+}
+
+var benchErr = errors.New("fail")
+
+func benchLogArgs(b *testing.B, info func(msg string, args ...any)) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		info("benchmark message", "int", 123, "str", "hello", "dur", time.Second, "err", benchErr)
+	}
+}
+
+func benchLogAttrs(b *testing.B, logAttrs func(ctx context.Context, lvl slog.Level, msg string, attrs ...slog.Attr)) {
+	b.ReportAllocs()
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		logAttrs(ctx, LevelInfo, "benchmark message",
+			slog.Int("int", 123), slog.String("str", "hello"), slog.Duration("dur", time.Second))
+	}
+}
+
+func BenchmarkLogger(b *testing.B) {
+	for _, bh := range benchHandlers {
+		b.Run(bh.name, func(b *testing.B) {
+			b.Run("slog/args", func(b *testing.B) {
+				benchLogArgs(b, slog.New(bh.new(io.Discard)).Info)
+			})
+			b.Run("protolog/args", func(b *testing.B) {
+				benchLogArgs(b, New(bh.new(io.Discard)).Info)
+			})
+			b.Run("protolog/args-nopc", func(b *testing.B) {
+				benchLogArgs(b, New(bh.new(io.Discard), SkipPCMod()).Info)
+			})
+			b.Run("slog/attrs", func(b *testing.B) {
+				benchLogAttrs(b, slog.New(bh.new(io.Discard)).LogAttrs)
+			})
+			b.Run("protolog/attrs", func(b *testing.B) {
+				benchLogAttrs(b, New(bh.new(io.Discard)).LogAttrs)
+			})
+			b.Run("slog/with", func(b *testing.B) {
+				benchLogArgs(b, slog.New(bh.new(io.Discard)).With("a", 1, "b", "two").Info)
+			})
+			b.Run("protolog/with", func(b *testing.B) {
+				benchLogArgs(b, New(bh.new(io.Discard)).With("a", 1, "b", "two").Info)
+			})
+		})
+	}
+}
+
+// benchService holds a Logger of which the concrete type is unknown to the compiler, like most users do.
+type benchService struct {
+	log Logger
+}
+
+//go:noinline
+func newBenchService(log Logger) *benchService {
+	return &benchService{log: log}
+}
+
+// BenchmarkLoggerDisabled calls the loggers directly, not through a function value,
+// since the arguments would otherwise escape to the heap.
+// New and With are inlined, so the compiler sees the concrete type of the Logger, and calls it without allocations.
+// Through a Logger interface of which the concrete type is unknown, e.g. in a struct field,
+// the compiler cannot prove that the variadic arguments do not escape, and allocates them at the call site,
+// unless the call is guarded with Enabled.
+func BenchmarkLoggerDisabled(b *testing.B) {
+	b.Run("slog", func(b *testing.B) {
+		b.ReportAllocs()
+		logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+		for i := 0; i < b.N; i++ {
+			logger.Debug("benchmark message", "int", 123, "str", "hello", "dur", time.Second, "err", benchErr)
+		}
+	})
+	b.Run("protolog", func(b *testing.B) {
+		b.ReportAllocs()
+		logger := New(JSONHandler(io.Discard), LevelMod(LevelInfo))
+		for i := 0; i < b.N; i++ {
+			logger.Debug("benchmark message", "int", 123, "str", "hello", "dur", time.Second, "err", benchErr)
+		}
+	})
+	b.Run("protolog/with", func(b *testing.B) {
+		b.ReportAllocs()
+		logger := New(JSONHandler(io.Discard), LevelMod(LevelInfo)).With("a", 1)
+		for i := 0; i < b.N; i++ {
+			logger.Debug("benchmark message", "int", 123, "str", "hello", "dur", time.Second, "err", benchErr)
+		}
+	})
+	b.Run("protolog/attrs", func(b *testing.B) {
+		b.ReportAllocs()
+		logger := New(JSONHandler(io.Discard), LevelMod(LevelInfo))
+		ctx := context.Background()
+		for i := 0; i < b.N; i++ {
+			logger.LogAttrs(ctx, LevelDebug, "benchmark message", slog.Int("int", 123), slog.String("str", "hello"))
+		}
+	})
+	b.Run("protolog/interface", func(b *testing.B) {
+		b.ReportAllocs()
+		s := newBenchService(New(JSONHandler(io.Discard), LevelMod(LevelInfo)))
+		for i := 0; i < b.N; i++ {
+			s.log.Debug("benchmark message", "int", 123, "str", "hello", "dur", time.Second, "err", benchErr)
+		}
+	})
+	b.Run("protolog/interface-enabled", func(b *testing.B) {
+		b.ReportAllocs()
+		s := newBenchService(New(JSONHandler(io.Discard), LevelMod(LevelInfo)))
+		ctx := context.Background()
+		for i := 0; i < b.N; i++ {
+			if s.log.Enabled(ctx, LevelDebug) {
+				s.log.Debug("benchmark message", "int", 123, "str", "hello", "dur", time.Second, "err", benchErr)
+			}
+		}
+	})
+}
+
+// BenchmarkHandler measures the handlers by themselves, without the logger.
+func BenchmarkHandler(b *testing.B) {
+	for _, bh := range benchHandlers {
+		b.Run(bh.name, func(b *testing.B) {
+			h := bh.new(io.Discard)
+			r := slog.NewRecord(time.Now(), LevelInfo, "benchmark message", 0)
+			r.AddAttrs(slog.Int("int", 123), slog.String("str", "hello"), slog.Duration("dur", time.Second))
+			ctx := context.Background()
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = h.Handle(ctx, r)
+			}
+		})
+	}
+}
+
+func BenchmarkLoggerWithContext(b *testing.B) {
+	b.ReportAllocs()
+	logger := New(JSONHandler(io.Discard), LevelMod(LevelInfo))
+	ctx := context.Background()
+	for i := 0; i < b.N; i++ {
+		_ = logger.WithContext(ctx)
+	}
+}
+
+func BenchmarkFormatSlogValue(b *testing.B) {
+	big256, _ := new(big.Int).SetString("-115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	values := []struct {
+		name string
+		v    slog.Value
+	}{
+		{"int", slog.IntValue(-1234567)},
+		{"uint64", slog.Uint64Value(18446744073709551615)},
+		{"float", slog.Float64Value(1234.5678)},
+		{"string", slog.StringValue("hello world")},
+		{"duration", slog.DurationValue(1234567 * time.Microsecond)},
+		{"time", slog.TimeValue(time.Now())},
+		{"bigint", slog.AnyValue(big.NewInt(123456789))},
+		{"bigint256", slog.AnyValue(big256)},
+		{"u256", slog.AnyValue(&testU256{1, 2, 3, 4})},
+		{"error", slog.AnyValue(benchErr)},
+		{"nil", slog.AnyValue((*big.Int)(nil))},
+	}
+	cfg := defaultFormatConfig
+	for _, tc := range values {
+		b.Run(tc.name, func(b *testing.B) {
+			buf := make([]byte, 0, 128)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf = cfg.FormatSlogValue(tc.v, buf[:0])
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package log
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/protolambda/proto-log/log/internal/logsettings"
+)
+
+// helpers is the set of names of functions that are marked with Helper.
+var helpers sync.Map
+
+// hasHelpers is set when the first function is marked with Helper,
+// so callers are only looked up by function name if necessary.
+var hasHelpers atomic.Bool
+
+// helperGen is incremented when a function is marked with Helper, to invalidate the cached helperPCs.
+var helperGen atomic.Uint64
+
+// helperPCs caches if a program counter is in a helper function, to resolve every caller only once.
+// The cache is reset when the helperGen changes.
+var helperPCs struct {
+	mu  sync.RWMutex
+	gen uint64
+	m   map[uintptr]bool
+}
+
+// maxHelperDepth is the max number of frames that are inspected to skip Helper functions.
+const maxHelperDepth = 32
+
+// Helper marks the calling function as a logging helper, like testing.T.Helper.
+// When the source of a log call is determined, helper functions are skipped,
+// so records point to the caller of the helper instead.
+// Helper may be called from multiple goroutines.
+func Helper() {
+	var pcs [1]uintptr
+	// skip [runtime.Callers, Helper]
+	if runtime.Callers(2, pcs[:]) == 0 {
+		return
+	}
+	name := runtime.FuncForPC(pcs[0]).Name()
+	if _, ok := helpers.Load(name); ok {
+		return
+	}
+	if _, loaded := helpers.LoadOrStore(name, struct{}{}); !loaded {
+		helperGen.Add(1)
+		hasHelpers.Store(true)
+	}
+}
+
+// CallerPC returns the program counter of the caller, for the source of a log record,
+// or 0 if the program counter capture is disabled.
+// Skip 0 identifies the caller of CallerPC, 1 the caller of that, and so on.
+// Functions that are marked with Helper are skipped.
+func CallerPC(skip int) uintptr {
+	if logsettings.IgnorePC {
+		return 0
+	}
+	if !hasHelpers.Load() {
+		var pcs [1]uintptr
+		// skip [runtime.Callers, CallerPC]
+		runtime.Callers(skip+2, pcs[:])
+		return pcs[0]
+	}
+	var pcs [maxHelperDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	for _, pc := range pcs[:n] {
+		if !isHelperPC(pc) {
+			return pc
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return pcs[n-1]
+}
+
+// isHelperPC checks if the program counter is in a function that is marked with Helper.
+func isHelperPC(pc uintptr) bool {
+	gen := helperGen.Load()
+	helperPCs.mu.RLock()
+	helper, ok := helperPCs.m[pc]
+	ok = ok && helperPCs.gen == gen
+	helperPCs.mu.RUnlock()
+	if ok {
+		return helper
+	}
+	// Callers expands inlined calls, so every pc maps to one logical function
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		_, helper = helpers.Load(fn.Name())
+	}
+	helperPCs.mu.Lock()
+	defer helperPCs.mu.Unlock()
+	if helperPCs.gen != gen {
+		if helperPCs.gen > gen {
+			return helper // a newer helper was marked meanwhile, do not cache the outdated result
+		}
+		helperPCs.gen = gen
+		helperPCs.m = nil
+	}
+	if helperPCs.m == nil {
+		helperPCs.m = make(map[uintptr]bool)
+	}
+	helperPCs.m[pc] = helper
+	return helper
+}
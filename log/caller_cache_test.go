@@ -0,0 +1,32 @@
+package log
+
+import (
+	"runtime"
+	"testing"
+)
+
+// lateHelper is only marked as helper when mark is set, after its program counter may already be cached.
+func lateHelper(mark bool) uintptr {
+	if mark {
+		Helper()
+	}
+	return CallerPC(0)
+}
+
+func TestHelperPCCache(t *testing.T) {
+	pc := lateHelper(false)
+	t.Cleanup(func() {
+		helpers.Delete(runtime.FuncForPC(pc).Name())
+		helperGen.Add(1)
+	})
+	if isHelperPC(pc) || isHelperPC(pc) {
+		t.Fatal("expected unmarked function to not be a helper")
+	}
+	if _, ok := helperPCs.m[pc]; !ok {
+		t.Fatal("expected program counter to be cached")
+	}
+	lateHelper(true)
+	if !isHelperPC(pc) {
+		t.Fatal("expected cached program counter to be invalidated when a helper is marked")
+	}
+}
@@ -0,0 +1,70 @@
+package log_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+// logHelper is marked as helper, so log calls within are attributed to the caller.
+func logHelper(logger log.Logger, msg string) {
+	log.Helper()
+	logger.Info(msg)
+}
+
+// nestedHelper calls another helper, both are skipped.
+func nestedHelper(logger log.Logger, msg string) {
+	log.Helper()
+	logHelper(logger, msg)
+}
+
+// skipHelper skips itself with WithCallerSkip.
+func skipHelper(logger log.Logger, msg string) {
+	logger.WithCallerSkip(1).Infof(msg)
+}
+
+func TestCallerSkip(t *testing.T) {
+	wd, _ := os.Getwd()
+	handlers := []struct {
+		name   string
+		new    func(w io.Writer, opts ...log.FormatOption) slog.Handler
+		source func(line int) string
+	}{
+		{"terminal", log.TerminalHandler, func(line int) string { return fmt.Sprintf("caller_test.go:%d", line) }},
+		{"logfmt", log.LogfmtHandler, func(line int) string { return fmt.Sprintf("source=caller_test.go:%d ", line) }},
+		{"json", log.JSONHandler, func(line int) string { return fmt.Sprintf(`"file":"caller_test.go","line":%d}`, line) }},
+	}
+	for _, h := range handlers {
+		t.Run(h.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := log.New(h.new(&buf, log.WithIncludeSource(true), log.WithSourceRelDir(wd), log.WithColor(false)))
+			check := func(line int) {
+				t.Helper()
+				assertSubstring(t, buf.String(), h.source(line))
+				buf.Reset()
+			}
+
+			_, _, line, _ := runtime.Caller(0)
+			logHelper(logger, "helper")
+			check(line + 1)
+
+			_, _, line, _ = runtime.Caller(0)
+			nestedHelper(logger, "nested")
+			check(line + 1)
+
+			_, _, line, _ = runtime.Caller(0)
+			skipHelper(logger, "skip")
+			check(line + 1)
+
+			_, _, line, _ = runtime.Caller(0)
+			logger.With("a", 1).Info("direct")
+			check(line + 1)
+		})
+	}
+}
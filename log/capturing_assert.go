@@ -0,0 +1,167 @@
+package log
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+)
+
+// maxClosestRecords is the number of closest-matching records to show when an assertion fails.
+const maxClosestRecords = 5
+
+// RequireLog asserts that a log record matching all filters was captured, and returns the first match.
+func RequireLog(t T, c Capturer, filters ...LogFilter) *CapturedRecord {
+	if h, ok := t.(helperT); ok {
+		h.Helper()
+	}
+	if rec := c.FindLog(filters...); rec != nil {
+		return rec
+	}
+	failWithRecords(t, "expected a matching log record, but found none. Closest captured records:", closestRecords(c, filters))
+	return nil
+}
+
+// RequireNoLog asserts that no log record matching all filters was captured.
+func RequireNoLog(t T, c Capturer, filters ...LogFilter) {
+	if h, ok := t.(helperT); ok {
+		h.Helper()
+	}
+	if recs := c.FindLogs(filters...); len(recs) > 0 {
+		failWithRecords(t, fmt.Sprintf("expected no matching log records, but found %d:", len(recs)), recs)
+	}
+}
+
+// RequireCount asserts that exactly n log records matching all filters were captured, and returns the matches.
+func RequireCount(t T, c Capturer, n int, filters ...LogFilter) []*CapturedRecord {
+	if h, ok := t.(helperT); ok {
+		h.Helper()
+	}
+	recs := c.FindLogs(filters...)
+	if len(recs) != n {
+		msg := fmt.Sprintf("expected %d matching log records, but found %d", n, len(recs))
+		if len(recs) < n {
+			failWithRecords(t, msg+". Closest captured records:", closestRecords(c, filters))
+		} else {
+			failWithRecords(t, msg+":", recs)
+		}
+	}
+	return recs
+}
+
+// RequireSequence asserts that log records matching each of the steps were captured in order.
+// Each step is matched by a different record, after the record of the previous step.
+// Use And to combine multiple filters into a single step.
+func RequireSequence(t T, c Capturer, steps ...LogFilter) []*CapturedRecord {
+	if h, ok := t.(helperT); ok {
+		h.Helper()
+	}
+	all := c.FindLogs()
+	out := make([]*CapturedRecord, 0, len(steps))
+	i := 0
+	for stepIndex, step := range steps {
+		for i < len(all) && !step(all[i]) {
+			i++
+		}
+		if i == len(all) {
+			var shown []*CapturedRecord
+			if len(out) > 0 {
+				shown = append(shown, out[len(out)-1])
+			}
+			if rec := c.FindLog(step); rec != nil {
+				shown = append(shown, rec)
+			}
+			msg := fmt.Sprintf("expected log sequence step %d of %d, but found no matching record after step %d."+
+				" Record of the previous step, and any out-of-order match of this step:",
+				stepIndex+1, len(steps), stepIndex)
+			failWithRecords(t, msg, shown)
+			return out
+		}
+		out = append(out, all[i])
+		i++
+	}
+	return out
+}
+
+// RequireNoLevelAbove asserts that no log records above the given level were captured.
+func RequireNoLevelAbove(t T, c Capturer, level slog.Level) {
+	if h, ok := t.(helperT); ok {
+		h.Helper()
+	}
+	if recs := c.FindLogs(MinLevelFilter(level + 1)); len(recs) > 0 {
+		failWithRecords(t, fmt.Sprintf("expected no log records above level %s, but found %d:",
+			strings.TrimSpace(LevelAlignedString(level)), len(recs)), recs)
+	}
+}
+
+// closestRecords returns the captured records that match the most filters, best match first.
+func closestRecords(c Capturer, filters []LogFilter) []*CapturedRecord {
+	type scored struct {
+		rec   *CapturedRecord
+		score int
+	}
+	var candidates []scored
+	for _, rec := range c.FindLogs() {
+		score := 0
+		for _, f := range filters {
+			if f(rec) {
+				score++
+			}
+		}
+		if score > 0 || len(filters) == 0 {
+			candidates = append(candidates, scored{rec: rec, score: score})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b scored) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	out := make([]*CapturedRecord, 0, maxClosestRecords)
+	for i := 0; i < len(candidates) && i < maxClosestRecords; i++ {
+		out = append(out, candidates[i].rec)
+	}
+	return out
+}
+
+// failWithRecords fails the test with the message, followed by the rendered records.
+func failWithRecords(t T, msg string, recs []*CapturedRecord) {
+	if h, ok := t.(helperT); ok {
+		h.Helper()
+	}
+	if len(recs) == 0 {
+		t.Error(msg + " (none)")
+	} else {
+		t.Error(msg + "\n" + RenderRecords(recs...))
+	}
+	t.FailNow()
+}
+
+// RenderRecords renders the captured records, including inherited attributes, with the TerminalHandler.
+func RenderRecords(recs ...*CapturedRecord) string {
+	wd, _ := os.Getwd()
+	return renderRecords(recs, WithIncludeSource(true), WithSourceRelDir(wd))
+}
+
+func renderRecords(recs []*CapturedRecord, opts ...FormatOption) string {
+	var buf bytes.Buffer
+	writeRecords(TerminalHandler(&buf, opts...), recs)
+	return buf.String()
+}
+
+// writeRecords handles the captured records with h, including the inherited attributes.
+func writeRecords(h slog.Handler, recs []*CapturedRecord) {
+	for _, rec := range recs {
+		var chain []*CapturedAttrs
+		for p := rec.Parent; p != nil; p = p.Parent {
+			chain = append(chain, p)
+		}
+		recH := h
+		for i := len(chain) - 1; i >= 0; i-- {
+			recH = recH.WithAttrs(chain[i].Attributes)
+		}
+		_ = recH.Handle(context.Background(), *rec.Record)
+	}
+}
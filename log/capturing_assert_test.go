@@ -0,0 +1,88 @@
+package log_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+// failT records test failures, and aborts the assertion with a panic on FailNow.
+type failT struct {
+	*testing.T
+	errs []string
+}
+
+type failNow struct{}
+
+func (f *failT) Error(args ...any) {
+	f.errs = append(f.errs, fmt.Sprint(args...))
+}
+
+func (f *failT) FailNow() {
+	panic(failNow{})
+}
+
+func (f *failT) Output() io.Writer {
+	return io.Discard
+}
+
+// expectFailure runs fn, and returns the failure message of the assertion in fn.
+func expectFailure(t *testing.T, fn func(t log.T)) string {
+	t.Helper()
+	ft := &failT{T: t}
+	func() {
+		defer func() {
+			if x := recover(); x != nil {
+				if _, ok := x.(failNow); !ok {
+					panic(x)
+				}
+			}
+		}()
+		fn(ft)
+	}()
+	if len(ft.errs) == 0 {
+		t.Fatal("expected assertion failure")
+	}
+	return ft.errs[0]
+}
+
+func TestRequireLog(t *testing.T) {
+	lgr := log.TestLogger(t, log.CapturingMod())
+	logs, ok := log.FindHandler[log.Capturer](lgr.Handler())
+	assertTrue(t, ok)
+
+	lgr.Info("peer connected", "peer", "abc")
+	lgr.With("svc", "p2p").Warn("peer dropped", "peer", "abc")
+	lgr.Info("block imported", "num", 123)
+
+	rec := log.RequireLog(t, logs, log.MessageFilter("peer dropped"))
+	assertEqual(t, rec.Record.Level, log.LevelWarn)
+	log.RequireNoLog(t, logs, log.LevelFilter(log.LevelError))
+	log.RequireCount(t, logs, 2, log.AttributesFilter("peer", "abc"))
+	log.RequireSequence(t, logs, log.MessageFilter("peer connected"), log.MessageFilter("block imported"))
+	log.RequireNoLevelAbove(t, logs, log.LevelWarn)
+
+	msg := expectFailure(t, func(ft log.T) {
+		log.RequireLog(ft, logs, log.MessageFilter("peer dropped"), log.AttributesFilter("peer", "def"))
+	})
+	assertSubstring(t, msg, "found none")
+	assertSubstring(t, msg, "peer dropped")
+	assertSubstring(t, msg, "svc=p2p")
+
+	msg = expectFailure(t, func(ft log.T) {
+		log.RequireSequence(ft, logs, log.MessageFilter("block imported"), log.MessageFilter("peer dropped"))
+	})
+	assertSubstring(t, msg, "step 2 of 2")
+
+	msg = expectFailure(t, func(ft log.T) {
+		log.RequireNoLevelAbove(ft, logs, log.LevelInfo)
+	})
+	assertSubstring(t, msg, "above level INFO, but found 1")
+
+	msg = expectFailure(t, func(ft log.T) {
+		log.RequireCount(ft, logs, 1, log.MessageContainsFilter("peer"))
+	})
+	assertSubstring(t, msg, "expected 1 matching log records, but found 2")
+}
@@ -1,8 +1,13 @@
 package log
 
 import (
+	"errors"
 	"log/slog"
+	"math/big"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 )
 
 type LogFilter func(record *CapturedRecord) bool
@@ -69,3 +74,165 @@ func ErrContainsFilter(errMessage string) LogFilter {
 		return found
 	}
 }
+
+// And matches records that match all the filters.
+func And(filters ...LogFilter) LogFilter {
+	return func(r *CapturedRecord) bool {
+		for _, f := range filters {
+			if !f(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches records that match any of the filters.
+func Or(filters ...LogFilter) LogFilter {
+	return func(r *CapturedRecord) bool {
+		for _, f := range filters {
+			if f(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches records that do not match the filter.
+func Not(filter LogFilter) LogFilter {
+	return func(r *CapturedRecord) bool {
+		return !filter(r)
+	}
+}
+
+// MinLevelFilter matches records of the given level or above.
+func MinLevelFilter(level slog.Level) LogFilter {
+	return func(r *CapturedRecord) bool {
+		return r.Record.Level >= level
+	}
+}
+
+// LevelRangeFilter matches records with a level between minLevel and maxLevel (both inclusive).
+func LevelRangeFilter(minLevel, maxLevel slog.Level) LogFilter {
+	return func(r *CapturedRecord) bool {
+		return r.Record.Level >= minLevel && r.Record.Level <= maxLevel
+	}
+}
+
+// HasAttr matches records with the attribute.
+// Dotted keys select attributes within groups, if there is no attribute with the full key.
+func HasAttr(key string) LogFilter {
+	return func(r *CapturedRecord) bool {
+		_, ok := lookupAttr(r, key)
+		return ok
+	}
+}
+
+// AttrPredicate matches records with the attribute, if the attribute value satisfies fn.
+// Dotted keys select attributes within groups, if there is no attribute with the full key.
+func AttrPredicate(key string, fn func(v slog.Value) bool) LogFilter {
+	return func(r *CapturedRecord) bool {
+		v, ok := lookupAttr(r, key)
+		return ok && fn(v)
+	}
+}
+
+// AttrEquals matches records with the attribute equal to the given value.
+// Integers, floats and *big.Int values are compared by number, regardless of type,
+// other values are compared as slog.Value. NaN is not equal to any value, including NaN.
+func AttrEquals[T comparable](key string, value T) LogFilter {
+	expected := slog.AnyValue(value)
+	expectedNum, expectedIsNum := attrNumber(expected)
+	return AttrPredicate(key, func(v slog.Value) bool {
+		if expectedIsNum {
+			num, ok := attrNumber(v)
+			return ok && num.Cmp(expectedNum) == 0
+		}
+		return v.Equal(expected)
+	})
+}
+
+// attrNumber converts integers, floats and *big.Int values to a number.
+// NaN and infinite floats are not converted.
+func attrNumber(v slog.Value) (*big.Float, bool) {
+	switch v.Kind() {
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64:
+		return queryNumber(v)
+	case slog.KindAny:
+		if _, ok := v.Any().(*big.Int); ok {
+			return queryNumber(v)
+		}
+	}
+	return nil, false
+}
+
+// AttrRegex matches records with the attribute, if the string form of the value matches the regular expression.
+func AttrRegex(key string, re *regexp.Regexp) LogFilter {
+	return AttrPredicate(key, func(v slog.Value) bool {
+		return re.MatchString(v.String())
+	})
+}
+
+// SourceFileFilter matches records that were logged from a file path ending with the given file path.
+// The source is resolved from the record, or from the source attribute of records that were read back.
+func SourceFileFilter(file string) LogFilter {
+	file = filepath.ToSlash(file)
+	return func(r *CapturedRecord) bool {
+		src := r.Source()
+		if src == nil {
+			v, ok := lookupAttr(r, slog.SourceKey)
+			if !ok {
+				return false
+			}
+			if src, ok = v.Any().(*slog.Source); !ok {
+				return false
+			}
+		}
+		got := filepath.ToSlash(src.File)
+		return got == file || strings.HasSuffix(got, "/"+file)
+	}
+}
+
+// TimeRangeFilter matches records with a time within [from, to).
+// A zero from or to leaves the range unbounded on that side.
+func TimeRangeFilter(from, to time.Time) LogFilter {
+	return func(r *CapturedRecord) bool {
+		if !from.IsZero() && r.Time.Before(from) {
+			return false
+		}
+		if !to.IsZero() && !r.Time.Before(to) {
+			return false
+		}
+		return true
+	}
+}
+
+// ErrIsFilter matches records with an error attribute, of any key, that matches the target with errors.Is.
+func ErrIsFilter(target error) LogFilter {
+	return errFilter(func(err error) bool {
+		return errors.Is(err, target)
+	})
+}
+
+// ErrAsFilter matches records with an error attribute, of any key, that matches the type E with errors.As.
+func ErrAsFilter[E error]() LogFilter {
+	return errFilter(func(err error) bool {
+		var target E
+		return errors.As(err, &target)
+	})
+}
+
+func errFilter(fn func(err error) bool) LogFilter {
+	return func(r *CapturedRecord) bool {
+		found := false
+		r.Attrs(func(a slog.Attr) bool {
+			if err, ok := a.Value.Resolve().Any().(error); ok && !isNilPointer(err) && fn(err) {
+				found = true
+				return false
+			}
+			return true
+		})
+		return found
+	}
+}
@@ -0,0 +1,216 @@
+package log
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// GoldenUpdateEnv is the environment variable that, when set to "1" or "true",
+// makes RequireGolden write the golden files instead of comparing against them.
+// A boolean "update" test flag, if defined by the test binary, has the same effect.
+const GoldenUpdateEnv = "PROTOLOG_UPDATE_GOLDEN"
+
+// goldenMask replaces the values of masked attributes.
+const goldenMask = "<masked>"
+
+// GoldenConfig configures how captured records are rendered for golden-file comparison.
+type GoldenConfig struct {
+	// IncludeSource renders the source file and line, relative to the working directory.
+	// This is disabled by default, since line numbers change with unrelated code edits.
+	IncludeSource bool
+	// MaskKeys are attribute keys of which the values are masked, e.g. random IDs.
+	MaskKeys []string
+	// MaskKinds are attribute value kinds that are masked, e.g. slog.KindDuration.
+	MaskKinds []slog.Kind
+	// Mask is an optional function to replace attributes before rendering.
+	Mask func(a slog.Attr) slog.Attr
+	// FormatOptions are applied to the TerminalHandler that renders the records.
+	FormatOptions []FormatOption
+}
+
+func (cfg *GoldenConfig) Apply(opts ...GoldenOption) {
+	for _, opt := range opts {
+		opt(cfg)
+	}
+}
+
+type GoldenOption func(cfg *GoldenConfig)
+
+// WithGoldenSource sets GoldenConfig.IncludeSource
+func WithGoldenSource(includeSource bool) GoldenOption {
+	return func(cfg *GoldenConfig) {
+		cfg.IncludeSource = includeSource
+	}
+}
+
+// WithGoldenMaskKeys adds to GoldenConfig.MaskKeys
+func WithGoldenMaskKeys(keys ...string) GoldenOption {
+	return func(cfg *GoldenConfig) {
+		cfg.MaskKeys = append(cfg.MaskKeys, keys...)
+	}
+}
+
+// WithGoldenMaskKinds adds to GoldenConfig.MaskKinds
+func WithGoldenMaskKinds(kinds ...slog.Kind) GoldenOption {
+	return func(cfg *GoldenConfig) {
+		cfg.MaskKinds = append(cfg.MaskKinds, kinds...)
+	}
+}
+
+// WithGoldenMask sets GoldenConfig.Mask
+func WithGoldenMask(mask func(a slog.Attr) slog.Attr) GoldenOption {
+	return func(cfg *GoldenConfig) {
+		cfg.Mask = mask
+	}
+}
+
+// WithGoldenFormat adds to GoldenConfig.FormatOptions
+func WithGoldenFormat(opts ...FormatOption) GoldenOption {
+	return func(cfg *GoldenConfig) {
+		cfg.FormatOptions = append(cfg.FormatOptions, opts...)
+	}
+}
+
+// RenderGolden renders all captured records deterministically: without time and color,
+// with relative source paths if enabled, and with masked attributes.
+func RenderGolden(c Capturer, opts ...GoldenOption) string {
+	var cfg GoldenConfig
+	cfg.Apply(opts...)
+	wd, _ := os.Getwd()
+	formatOpts := append([]FormatOption{
+		WithColor(false),
+		WithExcludeTime(true),
+		WithIncludeSource(cfg.IncludeSource),
+		WithSourceRelDir(wd),
+	}, cfg.FormatOptions...)
+	// masking is applied to copies, the captured records are not modified
+	parents := make(map[*CapturedAttrs]*CapturedAttrs)
+	var maskParent func(p *CapturedAttrs) *CapturedAttrs
+	maskParent = func(p *CapturedAttrs) *CapturedAttrs {
+		if p == nil {
+			return nil
+		}
+		if out, ok := parents[p]; ok {
+			return out
+		}
+		out := &CapturedAttrs{Parent: maskParent(p.Parent), Attributes: cfg.maskAttrs(p.Attributes)}
+		parents[p] = out
+		return out
+	}
+	var recs []*CapturedRecord
+	for _, rec := range c.FindLogs() {
+		masked := slog.NewRecord(rec.Time, rec.Level, rec.Message, rec.PC)
+		rec.Record.Attrs(func(a slog.Attr) bool {
+			masked.AddAttrs(cfg.maskAttr(a))
+			return true
+		})
+		recs = append(recs, &CapturedRecord{Parent: maskParent(rec.Parent), Record: &masked})
+	}
+	return renderRecords(recs, formatOpts...)
+}
+
+func (cfg *GoldenConfig) maskAttrs(attrs []slog.Attr) []slog.Attr {
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = cfg.maskAttr(a)
+	}
+	return out
+}
+
+func (cfg *GoldenConfig) maskAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if slices.Contains(cfg.MaskKeys, a.Key) || slices.Contains(cfg.MaskKinds, a.Value.Kind()) {
+		a.Value = slog.StringValue(goldenMask)
+	} else if a.Value.Kind() == slog.KindGroup {
+		a.Value = slog.GroupValue(cfg.maskAttrs(a.Value.Group())...)
+	}
+	if cfg.Mask != nil {
+		a = cfg.Mask(a)
+	}
+	return a
+}
+
+// RequireGolden asserts that the rendered captured records, see RenderGolden, match the golden file at path.
+// If the GoldenUpdateEnv environment variable or "update" test flag is set,
+// the golden file is written instead.
+func RequireGolden(t T, c Capturer, path string, opts ...GoldenOption) {
+	if h, ok := t.(helperT); ok {
+		h.Helper()
+	}
+	got := RenderGolden(c, opts...)
+	if goldenUpdate() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Error("failed to create golden file dir:", err)
+			t.FailNow()
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Error("failed to write golden file:", err)
+			t.FailNow()
+		}
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Error(fmt.Sprintf("failed to read golden file (set %s=1 to create it): %v", GoldenUpdateEnv, err))
+		t.FailNow()
+		return
+	}
+	if want := string(data); got != want {
+		t.Error(fmt.Sprintf("captured logs do not match golden file %s (set %s=1 to update it):\n%s",
+			path, GoldenUpdateEnv, lineDiff(want, got)))
+		t.FailNow()
+	}
+}
+
+func goldenUpdate() bool {
+	switch strings.ToLower(os.Getenv(GoldenUpdateEnv)) {
+	case "1", "true":
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		return f.Value.String() == "true"
+	}
+	return false
+}
+
+// lineDiff returns a minimal line-based diff, with "-" for removed and "+" for added lines.
+func lineDiff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	// longest common subsequence table
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			out.WriteString("+ " + b[j] + "\n")
+			j++
+		default:
+			out.WriteString("- " + a[i] + "\n")
+			i++
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,39 @@
+package log_test
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func logStartup(lgr log.Logger) {
+	lgr.Info("starting node", "version", "v1.2.3", "id", time.Now().UnixNano())
+	sub := lgr.With("svc", "p2p")
+	sub.Debug("listening", "addr", "127.0.0.1:9000", "took", 1234*time.Microsecond)
+	sub.Warn("no peers", slog.Group("peer", "id", time.Now().UnixNano(), "count", 0))
+}
+
+func TestRequireGolden(t *testing.T) {
+	lgr := log.TestLogger(t, log.CapturingMod())
+	logs, ok := log.FindHandler[log.Capturer](lgr.Handler())
+	assertTrue(t, ok)
+	logStartup(lgr)
+
+	masks := []log.GoldenOption{log.WithGoldenMaskKeys("id"), log.WithGoldenMaskKinds(slog.KindDuration)}
+	log.RequireGolden(t, logs, filepath.Join("testdata", "golden_startup.txt"), masks...)
+
+	// a golden file that does not match shows a diff
+	t.Setenv(log.GoldenUpdateEnv, "")
+	path := filepath.Join(t.TempDir(), "golden.txt")
+	assertTrue(t, os.WriteFile(path, []byte(log.RenderGolden(logs, masks...)), 0o644) == nil)
+	lgr.Error("unexpected")
+	msg := expectFailure(t, func(ft log.T) {
+		log.RequireGolden(ft, logs, path, masks...)
+	})
+	assertSubstring(t, msg, "+ ERROR unexpected")
+	assertSubstring(t, msg, "  INFO  starting node")
+}
@@ -0,0 +1,415 @@
+package log
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseLogFilter compiles a filter query into a LogFilter.
+//
+// A query is a boolean expression of comparisons, combined with &&, || and !,
+// and grouped with parentheses:
+//
+//	lvl>=warn && msg~"peer" && (peer.id=="abc" || err~timeout)
+//
+// The left-hand side of a comparison is one of:
+//   - lvl (or level): the record level, compared by severity, e.g. lvl>=warn
+//   - msg (or message): the record message
+//   - t (or time): the record time, e.g. t>="2024-01-02T15:04:05Z"
+//   - any other key: a record attribute, with dots to select attributes within groups, e.g. peer.id
+//
+// The operators are:
+//   - == and != for equality. Numbers, durations and times are compared by value, other values as strings.
+//   - <, <=, > and >= for numbers (including *big.Int), durations and times.
+//   - ~ and !~ for substring matching.
+//   - =~ for regular expression matching.
+//
+// A key without operator checks for the presence of the attribute, e.g. !err
+//
+// Values are either quoted strings, or bare words that end at whitespace or a parenthesis.
+func ParseLogFilter(query string) (LogFilter, error) {
+	p := &queryParser{src: query}
+	f, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return f, nil
+}
+
+// MustParseLogFilter is like ParseLogFilter, but panics if the query is invalid.
+func MustParseLogFilter(query string) LogFilter {
+	f, err := ParseLogFilter(query)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+type queryParser struct {
+	src string
+	pos int
+}
+
+func (p *queryParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid filter query at position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// peek returns the next rune and its size, or size 0 at the end of the query.
+func (p *queryParser) peek() (rune, int) {
+	return utf8.DecodeRuneInString(p.src[p.pos:])
+}
+
+func (p *queryParser) skipSpace() {
+	for {
+		c, size := p.peek()
+		if size == 0 || !unicode.IsSpace(c) {
+			return
+		}
+		p.pos += size
+	}
+}
+
+// consume skips whitespace, and consumes tok if it is next.
+func (p *queryParser) consume(tok string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], tok) {
+		p.pos += len(tok)
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) parseOr() (LogFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or(left, right)
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (LogFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.consume("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = And(left, right)
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseUnary() (LogFilter, error) {
+	if p.consume("!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(inner), nil
+	}
+	if p.consume("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+// operators, longest first
+var queryOperators = []string{"==", "!=", "<=", ">=", "=~", "!~", "<", ">", "~"}
+
+func (p *queryParser) parseComparison() (LogFilter, error) {
+	p.skipSpace()
+	start := p.pos
+	for {
+		c, size := p.peek()
+		if size == 0 || !(unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.' || c == '-') {
+			break
+		}
+		p.pos += size
+	}
+	key := p.src[start:p.pos]
+	if key == "" {
+		return nil, p.errorf("expected key")
+	}
+	p.skipSpace()
+	op := ""
+	for _, candidate := range queryOperators {
+		if strings.HasPrefix(p.src[p.pos:], candidate) {
+			op = candidate
+			p.pos += len(candidate)
+			break
+		}
+	}
+	if op == "" {
+		return HasAttr(key), nil
+	}
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	var f LogFilter
+	switch key {
+	case "lvl", "level":
+		f, err = levelQueryFilter(op, value)
+	case "msg", "message":
+		f, err = valueQueryFilter(op, value, func(r *CapturedRecord) (slog.Value, bool) {
+			return slog.StringValue(r.Message), true
+		})
+	case "t", "time":
+		f, err = valueQueryFilter(op, value, func(r *CapturedRecord) (slog.Value, bool) {
+			return slog.TimeValue(r.Time), true
+		})
+	default:
+		f, err = valueQueryFilter(op, value, func(r *CapturedRecord) (slog.Value, bool) {
+			return lookupAttr(r, key)
+		})
+	}
+	if err != nil {
+		return nil, p.errorf("%v", err)
+	}
+	return f, nil
+}
+
+func (p *queryParser) parseValue() (string, error) {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == '"' {
+		end := quotedEnd(p.src[p.pos:])
+		if end < 0 {
+			return "", p.errorf("unterminated string")
+		}
+		v, err := strconv.Unquote(p.src[p.pos : p.pos+end])
+		if err != nil {
+			return "", p.errorf("invalid string: %v", err)
+		}
+		p.pos += end
+		return v, nil
+	}
+	start := p.pos
+	for {
+		c, size := p.peek()
+		if size == 0 || unicode.IsSpace(c) || c == '(' || c == ')' ||
+			strings.HasPrefix(p.src[p.pos:], "&&") || strings.HasPrefix(p.src[p.pos:], "||") {
+			break
+		}
+		p.pos += size
+	}
+	if start == p.pos {
+		return "", p.errorf("expected value")
+	}
+	return p.src[start:p.pos], nil
+}
+
+// lookupAttr finds the attribute with the given key.
+// Dotted keys select attributes within groups, if there is no attribute with the full key.
+func lookupAttr(r *CapturedRecord, key string) (out slog.Value, ok bool) {
+	first, rest, dotted := strings.Cut(key, ".")
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == key {
+			out, ok = a.Value.Resolve(), true
+			return false
+		}
+		if dotted && a.Key == first {
+			if v, found := lookupGroup(a.Value.Resolve(), rest); found {
+				out, ok = v, true
+				return false
+			}
+		}
+		return true
+	})
+	return
+}
+
+func lookupGroup(v slog.Value, key string) (slog.Value, bool) {
+	if v.Kind() != slog.KindGroup {
+		return slog.Value{}, false
+	}
+	first, rest, dotted := strings.Cut(key, ".")
+	for _, a := range v.Group() {
+		if a.Key == key {
+			return a.Value.Resolve(), true
+		}
+		if dotted && a.Key == first {
+			if inner, ok := lookupGroup(a.Value.Resolve(), rest); ok {
+				return inner, true
+			}
+		}
+	}
+	return slog.Value{}, false
+}
+
+func levelQueryFilter(op string, value string) (LogFilter, error) {
+	lvl, err := LevelFromString(value)
+	if err != nil {
+		n, nErr := strconv.Atoi(value)
+		if nErr != nil {
+			return nil, err
+		}
+		lvl = slog.Level(n)
+	}
+	check, err := compareOp(op)
+	if err != nil {
+		return nil, err
+	}
+	return func(r *CapturedRecord) bool {
+		return check(cmp.Compare(r.Level, lvl))
+	}, nil
+}
+
+// compareOp returns a function that checks the comparison result of the ordering operator.
+func compareOp(op string) (func(c int) bool, error) {
+	switch op {
+	case "==":
+		return func(c int) bool { return c == 0 }, nil
+	case "!=":
+		return func(c int) bool { return c != 0 }, nil
+	case "<":
+		return func(c int) bool { return c < 0 }, nil
+	case "<=":
+		return func(c int) bool { return c <= 0 }, nil
+	case ">":
+		return func(c int) bool { return c > 0 }, nil
+	case ">=":
+		return func(c int) bool { return c >= 0 }, nil
+	default:
+		return nil, fmt.Errorf("operator %q is not supported here", op)
+	}
+}
+
+func valueQueryFilter(op string, value string, get func(r *CapturedRecord) (slog.Value, bool)) (LogFilter, error) {
+	switch op {
+	case "~", "!~":
+		negate := op == "!~"
+		return func(r *CapturedRecord) bool {
+			v, ok := get(r)
+			return ok && strings.Contains(v.String(), value) != negate
+		}, nil
+	case "=~":
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(r *CapturedRecord) bool {
+			v, ok := get(r)
+			return ok && re.MatchString(v.String())
+		}, nil
+	}
+	check, err := compareOp(op)
+	if err != nil {
+		return nil, err
+	}
+	ordered := op != "==" && op != "!="
+	num, isNum := parseQueryNumber(value)
+	dur, durErr := time.ParseDuration(value)
+	t, timeErr := parseQueryTime(value)
+	if ordered && !isNum && durErr != nil && timeErr != nil {
+		return nil, fmt.Errorf("operator %q requires a number, duration or time, got %q", op, value)
+	}
+	return func(r *CapturedRecord) bool {
+		v, ok := get(r)
+		if !ok {
+			return false
+		}
+		switch {
+		case v.Kind() == slog.KindDuration && durErr == nil:
+			return check(cmp.Compare(v.Duration(), dur))
+		case v.Kind() == slog.KindTime && timeErr == nil:
+			return check(v.Time().Compare(t))
+		case isNum:
+			if x, ok := queryNumber(v); ok {
+				return check(x.Cmp(num))
+			}
+		}
+		if ordered {
+			return false
+		}
+		return check(strings.Compare(v.String(), value))
+	}, nil
+}
+
+func parseQueryTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, timeFormat, time.DateTime, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+// parseQueryNumber parses s as number. Thousand-separators are allowed.
+func parseQueryNumber(s string) (*big.Float, bool) {
+	s = strings.ReplaceAll(s, "_", "")
+	f, ok := new(big.Float).SetPrec(256).SetString(s)
+	if !ok || f.IsInf() {
+		return nil, false
+	}
+	return f, true
+}
+
+// queryNumber converts a numeric attribute value to a number.
+// Strings, big-integers and other stringers are parsed.
+func queryNumber(v slog.Value) (*big.Float, bool) {
+	switch v.Kind() {
+	case slog.KindInt64:
+		return new(big.Float).SetPrec(256).SetInt64(v.Int64()), true
+	case slog.KindUint64:
+		return new(big.Float).SetPrec(256).SetUint64(v.Uint64()), true
+	case slog.KindFloat64:
+		// NaN has no big.Float representation, and infinities are rejected
+		// like they are when parsed from the query.
+		f := v.Float64()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, false
+		}
+		return new(big.Float).SetPrec(256).SetFloat64(f), true
+	case slog.KindString:
+		return parseQueryNumber(v.String())
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case *big.Int:
+			if x == nil {
+				return nil, false
+			}
+			return new(big.Float).SetPrec(256).SetInt(x), true
+		case u256:
+			if isNilPointer(x) {
+				return nil, false
+			}
+			return parseQueryNumber(x.Dec())
+		case fmt.Stringer:
+			if isNilPointer(x) {
+				return nil, false
+			}
+			return parseQueryNumber(x.String())
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,116 @@
+package log_test
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func TestParseLogFilter(t *testing.T) {
+	lgr := log.TestLogger(t, log.CapturingMod())
+	logs, ok := log.FindHandler[log.Capturer](lgr.Handler())
+	assertTrue(t, ok)
+
+	lgr.Info("connected to peer", slog.Group("peer", "id", "abc"), "count", 3)
+	lgr.Warn("peer timeout", slog.Group("peer", "id", "def"), "err", errors.New("i/o timeout"), "count", 10)
+	lgr.Error("bad block", "num", new(big.Int).SetUint64(1_000_000), "took", 2*time.Second)
+	lgr.Debug("trace info", "count", "7")
+
+	testCases := []struct {
+		query string
+		count int
+	}{
+		{`lvl>=warn`, 2},
+		{`level==info`, 1},
+		{`lvl>=warn && msg~"peer" && (peer.id=="abc" || err~timeout)`, 1},
+		{`peer.id==abc || peer.id==def`, 2},
+		{`!err && msg=~"^(connected|bad)"`, 2},
+		{`count>=7`, 2},
+		{`count<7`, 1},
+		{`num>999_999`, 1},
+		{`took>=1s`, 1},
+		{`took>1m`, 0},
+		{`err`, 1},
+		{`msg!~peer`, 2},
+		{`t>="2000-01-01"`, 4},
+		{`t<2000-01-01`, 0},
+	}
+	for _, tc := range testCases {
+		f, err := log.ParseLogFilter(tc.query)
+		if err != nil {
+			t.Fatalf("query %q: %v", tc.query, err)
+		}
+		if got := len(logs.FindLogs(f)); got != tc.count {
+			t.Errorf("query %q: expected %d matches, got %d", tc.query, tc.count, got)
+		}
+	}
+
+	for _, query := range []string{``, `lvl>=`, `(msg~a`, `lvl>=bogus`, `count>abc`, `msg=~"("`, `a==b c`} {
+		_, err := log.ParseLogFilter(query)
+		if err == nil {
+			t.Errorf("expected error for query %q", query)
+		}
+	}
+}
+
+func TestParseLogFilterUnicode(t *testing.T) {
+	lgr := log.TestLogger(t, log.CapturingMod())
+	logs, ok := log.FindHandler[log.Capturer](lgr.Handler())
+	assertTrue(t, ok)
+
+	lgr.Info("größe", "größe", "groß", "ключ", "значение")
+	lgr.Info("size", "größe", "klein")
+
+	testCases := []struct {
+		query string
+		count int
+	}{
+		{`größe==groß`, 1},
+		{`größe`, 2},
+		{`ключ==значение && msg==größe`, 1},
+		{"größe == klein", 1}, // non-breaking spaces
+	}
+	for _, tc := range testCases {
+		f, err := log.ParseLogFilter(tc.query)
+		if err != nil {
+			t.Fatalf("query %q: %v", tc.query, err)
+		}
+		if got := len(logs.FindLogs(f)); got != tc.count {
+			t.Errorf("query %q: expected %d matches, got %d", tc.query, tc.count, got)
+		}
+	}
+}
+
+func TestParseLogFilterNaN(t *testing.T) {
+	lgr := log.TestLogger(t, log.CapturingMod())
+	logs, ok := log.FindHandler[log.Capturer](lgr.Handler())
+	assertTrue(t, ok)
+
+	lgr.Info("nan", "x", math.NaN())
+	lgr.Info("inf", "x", math.Inf(1))
+	lgr.Info("one", "x", 1.0)
+
+	testCases := []struct {
+		query string
+		count int
+	}{
+		{`x>0`, 1},
+		{`x==1`, 1},
+		{`x!=1`, 2},
+		{`x<=1`, 1},
+	}
+	for _, tc := range testCases {
+		f, err := log.ParseLogFilter(tc.query)
+		if err != nil {
+			t.Fatalf("query %q: %v", tc.query, err)
+		}
+		if got := len(logs.FindLogs(f)); got != tc.count {
+			t.Errorf("query %q: expected %d matches, got %d", tc.query, tc.count, got)
+		}
+	}
+}
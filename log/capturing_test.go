@@ -1,7 +1,14 @@
 package log_test
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/big"
+	"regexp"
 	"testing"
+	"time"
 
 	"github.com/protolambda/proto-log/log"
 )
@@ -92,3 +99,57 @@ func TestCaptureLoggerNested(t *testing.T) {
 	assertEqual(t, len(logs.FindLogs(
 		log.AttributesFilter("a", "test"))), 1) // root logger logged 'a' once
 }
+
+func TestAttrEqualsNaN(t *testing.T) {
+	lgr := log.TestLogger(t, log.CapturingMod())
+	logs, ok := log.FindHandler[log.Capturer](lgr.Handler())
+	assertTrue(t, ok)
+
+	lgr.Info("a", "x", math.NaN())
+	lgr.Info("b", "x", 1.0)
+	lgr.Info("c", "x", math.Inf(1))
+
+	count := func(filters ...log.LogFilter) int {
+		return len(logs.FindLogs(filters...))
+	}
+	assertEqual(t, count(log.AttrEquals("x", 1)), 1)
+	assertEqual(t, count(log.AttrEquals("x", math.NaN())), 0)
+	assertEqual(t, count(log.AttrEquals("x", math.Inf(1))), 1)
+	assertEqual(t, count(log.AttrEquals("x", math.Inf(-1))), 0)
+}
+
+type testErr struct{ code int }
+
+func (e *testErr) Error() string { return fmt.Sprintf("test error %d", e.code) }
+
+func TestCaptureLoggerTypedFilters(t *testing.T) {
+	lgr := log.TestLogger(t, log.CapturingMod())
+	logs, ok := log.FindHandler[log.Capturer](lgr.Handler())
+	assertTrue(t, ok)
+
+	errNotFound := errors.New("not found")
+	start := time.Now()
+	lgr.Debug("a", "n", 1, "big", big.NewInt(42))
+	lgr.Info("b", "n", uint8(2), "reason", fmt.Errorf("wrapped: %w", errNotFound))
+	lgr.Warn("c", "n", 3, "cause", &testErr{code: 7}, slog.Group("peer", "id", "abc"))
+	lgr.Error("d", "name", "block-123")
+
+	count := func(filters ...log.LogFilter) int {
+		return len(logs.FindLogs(filters...))
+	}
+	assertEqual(t, count(log.MinLevelFilter(log.LevelInfo)), 3)
+	assertEqual(t, count(log.LevelRangeFilter(log.LevelInfo, log.LevelWarn)), 2)
+	assertEqual(t, count(log.AttrEquals("n", 2)), 1)
+	assertEqual(t, count(log.AttrEquals("big", big.NewInt(42))), 1)
+	assertEqual(t, count(log.AttrPredicate("n", func(v slog.Value) bool { return v.Kind() == slog.KindInt64 && v.Int64() >= 2 })), 1)
+	assertEqual(t, count(log.AttrRegex("name", regexp.MustCompile(`^block-\d+$`))), 1)
+	assertEqual(t, count(log.HasAttr("peer.id")), 1)
+	assertEqual(t, count(log.ErrIsFilter(errNotFound)), 1)
+	assertEqual(t, count(log.ErrAsFilter[*testErr]()), 1)
+	assertEqual(t, count(log.SourceFileFilter("capturing_test.go")), 4)
+	assertEqual(t, count(log.SourceFileFilter("other.go")), 0)
+	assertEqual(t, count(log.TimeRangeFilter(start, time.Time{})), 4)
+	assertEqual(t, count(log.TimeRangeFilter(time.Time{}, start)), 0)
+	assertEqual(t, count(log.Or(log.MessageFilter("a"), log.MessageFilter("d"))), 2)
+	assertEqual(t, count(log.And(log.HasAttr("n"), log.Not(log.LevelFilter(log.LevelDebug)))), 2)
+}
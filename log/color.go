@@ -0,0 +1,176 @@
+package log
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorSupport is the color capability of a terminal.
+type ColorSupport uint8
+
+const (
+	ColorSupportNone ColorSupport = iota
+	ColorSupport16
+	ColorSupport256
+	ColorSupportTrueColor
+)
+
+// ColorTheme configures the ANSI escape codes used by the TerminalHandler.
+// Empty codes are not colored.
+type ColorTheme struct {
+	Trace string
+	Debug string
+	Info  string
+	Warn  string
+	Error string
+	Crit  string
+	// Key colors attribute keys. If empty, keys are colored like the level.
+	Key string
+}
+
+// LevelColor returns the color code for the given level, or an empty string if not colored.
+func (t *ColorTheme) LevelColor(l slog.Level) string {
+	switch l {
+	case LevelCrit:
+		return t.Crit
+	case slog.LevelError:
+		return t.Error
+	case slog.LevelWarn:
+		return t.Warn
+	case slog.LevelInfo:
+		return t.Info
+	case slog.LevelDebug:
+		return t.Debug
+	case LevelTrace:
+		return t.Trace
+	default:
+		return ""
+	}
+}
+
+// Color256 returns the escape code to use color n of the 256-color palette as foreground color.
+func Color256(n uint8) string {
+	return "\x1b[38;5;" + strconv.Itoa(int(n)) + "m"
+}
+
+// ColorRGB returns the escape code to use a truecolor foreground color.
+func ColorRGB(r, g, b uint8) string {
+	return "\x1b[38;2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b)) + "m"
+}
+
+// DefaultColorTheme uses the basic 16-color palette, supported by nearly all terminals.
+var DefaultColorTheme = ColorTheme{
+	Trace: "\x1b[34m",
+	Debug: "\x1b[36m",
+	Info:  "\x1b[32m",
+	Warn:  "\x1b[33m",
+	Error: "\x1b[31m",
+	Crit:  "\x1b[35m",
+}
+
+// Color256Theme uses the 256-color palette.
+var Color256Theme = ColorTheme{
+	Trace: Color256(63),
+	Debug: Color256(44),
+	Info:  Color256(78),
+	Warn:  Color256(214),
+	Error: Color256(203),
+	Crit:  Color256(201),
+	Key:   Color256(110),
+}
+
+// TrueColorTheme uses 24-bit colors.
+var TrueColorTheme = ColorTheme{
+	Trace: ColorRGB(110, 118, 255),
+	Debug: ColorRGB(64, 200, 216),
+	Info:  ColorRGB(98, 214, 126),
+	Warn:  ColorRGB(255, 184, 64),
+	Error: ColorRGB(255, 92, 92),
+	Crit:  ColorRGB(255, 64, 255),
+	Key:   ColorRGB(140, 170, 210),
+}
+
+// ThemeFor returns the builtin theme that fits the color support,
+// or nil if there is no color support.
+func ThemeFor(support ColorSupport) *ColorTheme {
+	switch support {
+	case ColorSupport16:
+		return &DefaultColorTheme
+	case ColorSupport256:
+		return &Color256Theme
+	case ColorSupportTrueColor:
+		return &TrueColorTheme
+	default:
+		return nil
+	}
+}
+
+// DetectColorSupport detects the color capability of the writer.
+//
+// The FORCE_COLOR environment variable takes precedence:
+// "0" or "false" disables colors, "1" enables 16 colors, "2" enables 256 colors, "3" enables truecolor,
+// and any other value enables colors, with the level of color support derived from COLORTERM and TERM.
+// Otherwise, a non-empty NO_COLOR environment variable disables colors (see https://no-color.org/).
+// Otherwise, colors are only enabled when wr is an *os.File that is a terminal,
+// and TERM is not "dumb". The level of color support is derived from COLORTERM and TERM.
+func DetectColorSupport(wr io.Writer) ColorSupport {
+	if force, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(force) {
+		case "0", "false":
+			return ColorSupportNone
+		case "1":
+			return ColorSupport16
+		case "2":
+			return ColorSupport256
+		case "3":
+			return ColorSupportTrueColor
+		default:
+			return max(ColorSupport16, termColorSupport())
+		}
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return ColorSupportNone
+	}
+	if !isTerminal(wr) {
+		return ColorSupportNone
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return ColorSupportNone
+	}
+	return max(ColorSupport16, termColorSupport())
+}
+
+// termColorSupport derives the color support from the COLORTERM and TERM environment variables.
+func termColorSupport() ColorSupport {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ColorSupportTrueColor
+	}
+	term := os.Getenv("TERM")
+	if strings.Contains(term, "truecolor") || strings.Contains(term, "24bit") || strings.Contains(term, "direct") {
+		return ColorSupportTrueColor
+	}
+	if strings.Contains(term, "256color") {
+		return ColorSupport256
+	}
+	if term == "" || term == "dumb" {
+		return ColorSupportNone
+	}
+	return ColorSupport16
+}
+
+// isTerminal checks if wr is a file that is a character device, like a TTY.
+func isTerminal(wr io.Writer) bool {
+	f, ok := wr.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
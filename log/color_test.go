@@ -0,0 +1,48 @@
+package log_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func TestDetectColorSupport(t *testing.T) {
+	var buf bytes.Buffer
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("COLORTERM", "")
+
+	t.Setenv("NO_COLOR", "1")
+	assertEqual(t, log.DetectColorSupport(&buf), log.ColorSupportNone)
+
+	t.Setenv("FORCE_COLOR", "1")
+	assertEqual(t, log.DetectColorSupport(&buf), log.ColorSupport16)
+	t.Setenv("FORCE_COLOR", "2")
+	assertEqual(t, log.DetectColorSupport(&buf), log.ColorSupport256)
+	t.Setenv("FORCE_COLOR", "true")
+	assertEqual(t, log.DetectColorSupport(&buf), log.ColorSupport256)
+	t.Setenv("FORCE_COLOR", "3")
+	assertEqual(t, log.DetectColorSupport(&buf), log.ColorSupportTrueColor)
+	t.Setenv("FORCE_COLOR", "0")
+	assertEqual(t, log.DetectColorSupport(&buf), log.ColorSupportNone)
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "false")
+	assertEqual(t, log.DetectColorSupport(&buf), log.ColorSupportNone)
+}
+
+func TestTerminalHandlerTheme(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "1")
+	t.Setenv("TERM", "xterm")
+	var buf bytes.Buffer
+	theme := log.ColorTheme{Info: log.Color256(78), Key: log.ColorRGB(1, 2, 3)}
+	logger := log.New(log.TerminalHandler(&buf, log.WithAutoColor(), log.WithTheme(&theme), log.WithExcludeTime(true)))
+	logger.Info("hello", "a", 1)
+	assertEqual(t, buf.String(), "\x1b[38;5;78mINFO \x1b[0m hello                                    \x1b[38;2;1;2;3ma\x1b[0m=1\n")
+
+	buf.Reset()
+	t.Setenv("FORCE_COLOR", "0")
+	logger = log.New(log.TerminalHandler(&buf, log.WithAutoColor(), log.WithExcludeTime(true)))
+	logger.Info("hello", "a", 1)
+	assertEqual(t, buf.String(), "INFO  hello                                    a=1\n")
+}
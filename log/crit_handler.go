@@ -0,0 +1,166 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+)
+
+// CritPolicy follows up a crit-level log record, after the record was handled by the inner handler.
+type CritPolicy func(ctx context.Context, r slog.Record, inner slog.Handler)
+
+// CritHandler runs the registered crit hooks, see CritHandler.OnCrit, and then the crit policy,
+// after handling crit-level log records.
+type CritHandler struct {
+	inner  slog.Handler
+	policy CritPolicy
+	hooks  *critHooks // shared among derived CritHandlers
+}
+
+var _ Handler = (*CritHandler)(nil)
+
+// CritMod follows up crit-level log records with the given policy.
+func CritMod(policy CritPolicy) HandlerMod {
+	return func(h slog.Handler) slog.Handler {
+		return &CritHandler{inner: h, policy: policy, hooks: new(critHooks)}
+	}
+}
+
+// CritExitMod flushes the log handlers and exits the process with the given exit code,
+// after handling crit-level log records.
+func CritExitMod(code int) HandlerMod {
+	return CritMod(ExitCritPolicy(code))
+}
+
+// CritPanicMod panics with a *CritError, after handling crit-level log records.
+func CritPanicMod() HandlerMod {
+	return CritMod(PanicCritPolicy())
+}
+
+func (h *CritHandler) Unwrap() slog.Handler {
+	return h.inner
+}
+
+func (h *CritHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *CritHandler) Handle(ctx context.Context, r slog.Record) error {
+	err := h.inner.Handle(ctx, r)
+	if r.Level >= LevelCrit {
+		h.hooks.run(ctx, r)
+		h.policy(ctx, r, h.inner)
+	}
+	return err
+}
+
+func (h *CritHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &CritHandler{
+		inner:  h.inner.WithAttrs(attrs),
+		policy: h.policy,
+		hooks:  h.hooks,
+	}
+}
+
+func (h *CritHandler) WithGroup(name string) slog.Handler {
+	return &CritHandler{
+		inner:  h.inner.WithGroup(name),
+		policy: h.policy,
+		hooks:  h.hooks,
+	}
+}
+
+// osExit is replaced in tests
+var osExit = os.Exit
+
+// ExitCritPolicy flushes all handlers, see FlushHandlers, and then exits the process with the given exit code.
+func ExitCritPolicy(code int) CritPolicy {
+	return func(ctx context.Context, r slog.Record, inner slog.Handler) {
+		_ = FlushHandlers(inner)
+		osExit(code)
+	}
+}
+
+// CritError is the panic value of the PanicCritPolicy.
+type CritError struct {
+	Record slog.Record
+}
+
+func (e *CritError) Error() string {
+	return "CRIT-level log: " + e.Record.Message
+}
+
+// PanicCritPolicy flushes all handlers, see FlushHandlers, and then panics with a *CritError.
+func PanicCritPolicy() CritPolicy {
+	return func(ctx context.Context, r slog.Record, inner slog.Handler) {
+		_ = FlushHandlers(inner)
+		panic(&CritError{Record: r.Clone()})
+	}
+}
+
+// Flusher is a handler that buffers log output, and can flush it.
+type Flusher interface {
+	Flush() error
+}
+
+// FlushHandlers flushes every handler in the handler stack that implements Flusher.
+// The first error is returned, after trying to flush all handlers.
+func FlushHandlers(h slog.Handler) (err error) {
+	for h != nil {
+		if f, ok := h.(Flusher); ok {
+			if fErr := f.Flush(); fErr != nil && err == nil {
+				err = fErr
+			}
+		}
+		unwrappable, ok := h.(Handler)
+		if !ok {
+			return err
+		}
+		h = unwrappable.Unwrap()
+	}
+	return err
+}
+
+// critHooks are the hooks of a CritHandler and the handlers derived from it.
+type critHooks struct {
+	mu    sync.Mutex
+	next  uint64
+	hooks map[uint64]HandlerFunc
+}
+
+// OnCrit registers a hook that runs on every crit-level log record handled by this CritHandler,
+// or by the handlers derived from it, or from the same CritMod, before the crit policy runs.
+// E.g. to flush or close resources before the process exits.
+// Use FindHandler to get the CritHandler of a logger.
+// The returned function removes the hook.
+func (h *CritHandler) OnCrit(fn HandlerFunc) (remove func()) {
+	c := h.hooks
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.hooks == nil {
+		c.hooks = make(map[uint64]HandlerFunc)
+	}
+	id := c.next
+	c.next++
+	c.hooks[id] = fn
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.hooks, id)
+	}
+}
+
+func (c *critHooks) run(ctx context.Context, r slog.Record) {
+	c.mu.Lock()
+	hooks := make([]HandlerFunc, 0, len(c.hooks))
+	for _, id := range slices.Sorted(maps.Keys(c.hooks)) { // in order of registration
+		hooks = append(hooks, c.hooks[id])
+	}
+	c.mu.Unlock()
+	for _, fn := range hooks {
+		fn(ctx, r)
+	}
+}
@@ -0,0 +1,94 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+type flushHandler struct {
+	slog.Handler
+	flushed int
+}
+
+func (h *flushHandler) Flush() error {
+	h.flushed++
+	return nil
+}
+
+func TestCritExitMod(t *testing.T) {
+	var exitCode int
+	osExit = func(code int) { exitCode = code }
+	defer func() { osExit = os.Exit }()
+
+	var buf bytes.Buffer
+	sink := &flushHandler{Handler: TerminalHandler(&buf)}
+	logger := New(sink, CritExitMod(3))
+
+	var hooked []string
+	critHandler, ok := FindHandler[*CritHandler](logger.Handler())
+	if !ok {
+		t.Fatal("expected crit handler")
+	}
+	remove := critHandler.OnCrit(func(ctx context.Context, r slog.Record) {
+		hooked = append(hooked, r.Message)
+	})
+	defer remove()
+	logger.Error("not fatal")
+	if exitCode != 0 || sink.flushed != 0 || len(hooked) != 0 {
+		t.Fatal("unexpected crit handling of error log")
+	}
+	logger.Crit("fatal")
+	if exitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d", exitCode)
+	}
+	if sink.flushed != 1 {
+		t.Fatal("expected handler to be flushed")
+	}
+	if len(hooked) != 1 || hooked[0] != "fatal" {
+		t.Fatalf("expected crit hook to run, got %v", hooked)
+	}
+
+	// derived handlers share the hooks, other crit handlers do not
+	logger.With("sub", true).Crit("sub")
+	New(TerminalHandler(&buf), CritExitMod(4)).Crit("other")
+	if len(hooked) != 2 || hooked[1] != "sub" {
+		t.Fatalf("expected crit hook to run for derived handler only, got %v", hooked)
+	}
+	remove()
+	logger.Crit("removed")
+	if len(hooked) != 2 {
+		t.Fatalf("expected removed crit hook to not run, got %v", hooked)
+	}
+}
+
+func TestCritPanicMod(t *testing.T) {
+	logger := New(TerminalHandler(io.Discard), CritPanicMod())
+	defer func() {
+		var critErr *CritError
+		if err, ok := recover().(error); !ok || !errors.As(err, &critErr) || critErr.Record.Message != "boom" {
+			t.Fatal("expected CritError panic")
+		}
+	}()
+	logger.Crit("boom")
+}
+
+func TestTestLoggerCritPolicy(t *testing.T) {
+	ft := &fakeT{}
+	logger := TestLogger(ft, CritMod(TestCritMarkFailed(ft)))
+	logger.Crit("marked")
+	if !ft.Failed() {
+		t.Fatal("expected test to be marked as failed")
+	}
+
+	ft = &fakeT{}
+	logger = TestLogger(ft, CritMod(TestCritAllow()))
+	logger.Crit("allowed")
+	if ft.Failed() {
+		t.Fatal("expected crit log to be allowed")
+	}
+}
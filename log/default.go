@@ -0,0 +1,126 @@
+package log
+
+import (
+	"context"
+	"io"
+	stdlog "log"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// root is the Logger installed with SetDefault, or nil if none is installed.
+var root atomic.Pointer[Logger]
+
+// defaultMu serializes SetDefault calls and restores,
+// since the slog and stdlib log defaults are changed together.
+var defaultMu sync.Mutex
+
+// Root returns the default Logger, as installed with SetDefault.
+// If no Logger is installed, this returns a Logger of the handler of slog.Default.
+func Root() Logger {
+	if l := root.Load(); l != nil {
+		return *l
+	}
+	return New(slog.Default().Handler())
+}
+
+// DefaultConfig configures how SetDefault installs a Logger.
+type DefaultConfig struct {
+	// StdLogLevel is the level at which output of the stdlib log package is logged.
+	StdLogLevel slog.Level
+	// NoStdLog leaves the output of the stdlib log package as-is.
+	NoStdLog bool
+}
+
+func (cfg *DefaultConfig) Apply(opts ...DefaultOption) {
+	for _, opt := range opts {
+		opt(cfg)
+	}
+}
+
+type DefaultOption func(cfg *DefaultConfig)
+
+// WithStdLogLevel sets DefaultConfig.StdLogLevel
+func WithStdLogLevel(level slog.Level) DefaultOption {
+	return func(cfg *DefaultConfig) {
+		cfg.StdLogLevel = level
+	}
+}
+
+// WithoutStdLog sets DefaultConfig.NoStdLog
+func WithoutStdLog() DefaultOption {
+	return func(cfg *DefaultConfig) {
+		cfg.NoStdLog = true
+	}
+}
+
+// SetDefault installs l as the Root logger, and as slog.Default, so slog.Info and the like log to l.
+// The output of the stdlib log package, like log.Printf, is redirected to l as well,
+// at LevelInfo by default, with the source of the log.Print call.
+//
+// The returned function restores the previous defaults, e.g. in a test cleanup:
+//
+//	t.Cleanup(log.SetDefault(log.TestLogger(t)))
+func SetDefault(l Logger, opts ...DefaultOption) (restore func()) {
+	if l == nil {
+		panic("nil Logger")
+	}
+	cfg := DefaultConfig{StdLogLevel: LevelInfo}
+	cfg.Apply(opts...)
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	prevRoot := root.Load()
+	prevSlog := slog.Default()
+	prevOut, prevFlags, prevPrefix := stdlog.Writer(), stdlog.Flags(), stdlog.Prefix()
+
+	root.Store(&l)
+	// slog.SetDefault redirects the stdlib log output to the handler as well,
+	// but only captures the source if the stdlib log flags ask for it, and at a global level.
+	slog.SetDefault(slog.New(l.Handler()))
+	if cfg.NoStdLog {
+		stdlog.SetOutput(prevOut)
+		stdlog.SetFlags(prevFlags)
+	} else {
+		_, skipPC := FindHandler[*SkipPCHandler](l.Handler())
+		stdlog.SetOutput(&stdLogWriter{h: l.Handler(), level: cfg.StdLogLevel, skipPC: skipPC})
+		stdlog.SetFlags(0) // the handler adds the time and source
+		stdlog.SetPrefix("")
+	}
+
+	return func() {
+		defaultMu.Lock()
+		defer defaultMu.Unlock()
+		root.Store(prevRoot)
+		slog.SetDefault(prevSlog)
+		stdlog.SetOutput(prevOut)
+		stdlog.SetFlags(prevFlags)
+		stdlog.SetPrefix(prevPrefix)
+	}
+}
+
+// stdLogWriter is the output of the stdlib log package, to log each line as a record.
+type stdLogWriter struct {
+	h      slog.Handler
+	level  slog.Level
+	skipPC bool
+}
+
+var _ io.Writer = (*stdLogWriter)(nil)
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	ctx := context.Background()
+	if !w.h.Enabled(ctx, w.level) {
+		return len(p), nil
+	}
+	var pc uintptr
+	if !w.skipPC {
+		// skip [this function, log.(*Logger).output, log.Print*]
+		pc = CallerPC(3)
+	}
+	r := slog.NewRecord(time.Now(), w.level, strings.TrimSuffix(string(p), "\n"), pc)
+	return len(p), w.h.Handle(ctx, r)
+}
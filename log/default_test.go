@@ -0,0 +1,47 @@
+package log_test
+
+import (
+	"io"
+	stdlog "log"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func TestSetDefault(t *testing.T) {
+	prevSlog := slog.Default()
+	prevOut := stdlog.Writer()
+
+	logger := log.New(log.JSONHandler(io.Discard), log.CapturingMod(), log.LevelMod(log.LevelDebug))
+	c, _ := log.FindHandler[*log.CapturingHandler](logger.Handler())
+	restore := log.SetDefault(logger, log.WithStdLogLevel(log.LevelDebug))
+	assertTrue(t, log.Root() == logger)
+
+	slog.Info("from slog", "a", 1)
+	rec := c.FindLog(log.MessageFilter("from slog"))
+	assertNotNil(t, rec)
+	assertEqual(t, rec.AttrValue("a"), any(int64(1)))
+
+	stdlog.Printf("from %s", "stdlib")
+	rec = c.FindLog(log.MessageFilter("from stdlib"))
+	assertNotNil(t, rec)
+	assertEqual(t, rec.Level, log.LevelDebug)
+	frame, _ := runtime.CallersFrames([]uintptr{rec.PC}).Next()
+	assertEqual(t, filepath.Base(frame.File), "default_test.go")
+	assertEqual(t, frame.Function, "github.com/protolambda/proto-log/log_test.TestSetDefault")
+
+	restore()
+	assertTrue(t, slog.Default() == prevSlog)
+	assertTrue(t, stdlog.Writer() == prevOut)
+	assertTrue(t, log.Root() != logger)
+}
+
+func TestSetDefaultWithoutStdLog(t *testing.T) {
+	prevOut := stdlog.Writer()
+	logger := log.New(log.JSONHandler(io.Discard))
+	t.Cleanup(log.SetDefault(logger, log.WithoutStdLog()))
+	assertTrue(t, stdlog.Writer() == prevOut)
+}
@@ -7,27 +7,41 @@ package log
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"math"
 	"math/big"
-	"path/filepath"
-	"reflect"
+	"math/bits"
 	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 )
 
 const (
 	timeFormat        = "2006-01-02T15:04:05-0700"
-	floatFormat       = 'f'
-	termSrcJust       = 25
 	termMsgJust       = 40
 	termCtxMaxPadding = 40
+	// termMaxBufferSize is the largest buffer that is kept for reuse, after formatting a record
+	termMaxBufferSize = 64 << 10
+	// termContinuation prefixes the lines of multi-line values, rendered below the record line
+	termContinuation = "  | "
 )
 
 // 40 spaces
 var spaces = []byte("                                        ")
 
+// writePadding writes n spaces.
+func writePadding(b *bytes.Buffer, n int) {
+	for n > len(spaces) {
+		b.Write(spaces)
+		n -= len(spaces)
+	}
+	b.Write(spaces[:n])
+}
+
 // TerminalStringer is an analogous interface to the stdlib stringer, allowing
 // own types to have custom shortened serialization formats when printed to the
 // screen.
@@ -43,22 +57,22 @@ func (h *terminalHandler) format(r slog.Record) []byte {
 	}
 	b := h.buf
 
-	msg := escapeMessage(r.Message)
-	var color = ""
+	var msgLines []string
+	msg := r.Message
+	if h.cfg.MultiLine {
+		msg, msgLines = splitLines(msg)
+	}
+	msg = escapeMessage(msg)
+	var color, keyColor string
 	if h.cfg.UseColor {
-		switch r.Level {
-		case LevelCrit:
-			color = "\x1b[35m"
-		case slog.LevelError:
-			color = "\x1b[31m"
-		case slog.LevelWarn:
-			color = "\x1b[33m"
-		case slog.LevelInfo:
-			color = "\x1b[32m"
-		case slog.LevelDebug:
-			color = "\x1b[36m"
-		case LevelTrace:
-			color = "\x1b[34m"
+		theme := h.cfg.Theme
+		if theme == nil {
+			theme = &DefaultColorTheme
+		}
+		color = theme.LevelColor(r.Level)
+		keyColor = color
+		if theme.Key != "" {
+			keyColor = theme.Key
 		}
 	}
 
@@ -66,17 +80,10 @@ func (h *terminalHandler) format(r slog.Record) []byte {
 		// prepend right-padded source info
 		s := r.Source()
 		if s != nil {
-			lineNum := strconv.FormatInt(int64(s.Line), 10)
-			file, err := filepath.Rel(h.cfg.SourceRelDir, s.File)
-			if err != nil {
-				file = s.File
-			}
-			prefixLen := len(file) + 1 + len(lineNum)
-			b.WriteString(file)
-			b.WriteRune(':')
-			b.WriteString(lineNum)
-			if prefixLen < termSrcJust {
-				b.Write(spaces[:termSrcJust-prefixLen])
+			src := h.cfg.sourceString(s)
+			b.WriteString(src)
+			if n := utf8.RuneCountInString(src); n < h.cfg.SourcePadding {
+				writePadding(b, h.cfg.SourcePadding-n)
 			}
 			b.WriteRune(' ')
 		}
@@ -105,187 +112,348 @@ func (h *terminalHandler) format(r slog.Record) []byte {
 		b.Write(spaces[:termMsgJust-length])
 	}
 	// print the attributes
-	h.formatAttributes(b, r, color)
+	blocks := h.formatAttributes(b, r, keyColor)
+
+	// print the multi-line message and attribute values, below the record line
+	for _, line := range msgLines {
+		b.WriteString(termContinuation)
+		b.WriteString(escapeMessage(line))
+		b.WriteByte('\n')
+	}
+	for _, block := range blocks {
+		b.WriteString(termContinuation)
+		if keyColor != "" {
+			b.WriteString(keyColor)
+			b.Write(appendEscapeString(b.AvailableBuffer(), block.key))
+			b.WriteString("\x1b[0m:\n")
+		} else {
+			b.Write(appendEscapeString(b.AvailableBuffer(), block.key))
+			b.WriteString(":\n")
+		}
+		for _, line := range block.lines {
+			b.WriteString(termContinuation)
+			b.WriteString("  ")
+			b.WriteString(escapeMessage(line))
+			b.WriteByte('\n')
+		}
+	}
 
 	return b.Bytes()
 }
 
-func (h *terminalHandler) formatAttributes(buf *bytes.Buffer, r slog.Record, color string) {
-	writeAttr := func(attr slog.Attr, last bool) {
+// multiLineAttr is an attribute value that is rendered as indented block below the record line.
+type multiLineAttr struct {
+	key   string
+	lines []string
+}
+
+// splitLines splits s into the first line and any remaining lines.
+// Trailing line-breaks are ignored.
+func splitLines(s string) (first string, rest []string) {
+	s = strings.TrimRight(s, "\r\n")
+	if !strings.Contains(s, "\n") {
+		return strings.TrimSuffix(s, "\r"), nil
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines[0], lines[1:]
+}
+
+// multiLineText returns the lines of the text representation of v,
+// if v is a string, error or stringer that spans multiple lines.
+func multiLineText(v slog.Value) ([]string, bool) {
+	v = v.Resolve()
+	var text string
+	switch v.Kind() {
+	case slog.KindString:
+		text = v.String()
+	case slog.KindAny:
+		x := v.Any()
+		if isNilPointer(x) {
+			return nil, false
+		}
+		switch x := x.(type) {
+		case error:
+			text = x.Error()
+		case TerminalStringer:
+			text = x.TerminalString()
+		case fmt.Stringer:
+			text = x.String()
+		default:
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	first, rest := splitLines(text)
+	if len(rest) == 0 {
+		return nil, false
+	}
+	return append([]string{first}, rest...), true
+}
+
+// formatAttributes writes the attributes and ends the record line.
+// Multi-line attribute values are returned, to be written after the record line.
+func (h *terminalHandler) formatAttributes(buf *bytes.Buffer, r slog.Record, keyColor string) (blocks []multiLineAttr) {
+	// padding of the previous attribute is only written when followed by another attribute
+	pending := 0
+	var writeAttr func(prefix string, attr slog.Attr)
+	writeAttr = func(prefix string, attr slog.Attr) {
+		if h.cfg.NestedFormat == NestedFlatten {
+			if v, ok := h.cfg.nestedValue(attr.Value); ok {
+				if v.Kind() != slog.KindGroup {
+					attr.Value = v // empty nested value
+				} else {
+					for _, inner := range v.Group() {
+						writeAttr(prefix+attr.Key+".", inner)
+					}
+					return
+				}
+			}
+		}
+		key := prefix + attr.Key
+		if pending > 0 {
+			buf.Write(spaces[:pending])
+		}
 		buf.WriteByte(' ')
 
-		if color != "" {
-			buf.WriteString(color)
-			buf.Write(appendEscapeString(buf.AvailableBuffer(), attr.Key))
+		if keyColor != "" {
+			buf.WriteString(keyColor)
+			buf.Write(appendEscapeString(buf.AvailableBuffer(), key))
 			buf.WriteString("\x1b[0m=")
 		} else {
-			buf.Write(appendEscapeString(buf.AvailableBuffer(), attr.Key))
+			buf.Write(appendEscapeString(buf.AvailableBuffer(), key))
 			buf.WriteByte('=')
 		}
-		val := FormatSlogValue(attr.Value, buf.AvailableBuffer())
-
-		padding := h.fieldPadding[attr.Key]
+		var val []byte
+		var lines []string
+		if h.cfg.MultiLine {
+			lines, _ = multiLineText(attr.Value)
+		}
+		if len(lines) > 0 {
+			blocks = append(blocks, multiLineAttr{key: key, lines: lines})
+			val = fmt.Appendf(buf.AvailableBuffer(), "<%d lines>", len(lines))
+		} else {
+			val = h.cfg.FormatSlogValue(attr.Value, buf.AvailableBuffer())
+		}
 
 		length := utf8.RuneCount(val)
-		if padding < length && length <= termCtxMaxPadding {
+		padding, fixed := h.cfg.FieldWidths[key]
+		if !fixed {
+			padding = h.fieldPadding[key]
+		}
+		if !fixed && padding < length && length <= termCtxMaxPadding {
 			padding = length
-			h.fieldPadding[attr.Key] = padding
+			h.fieldPadding[key] = padding
 		}
 		buf.Write(val)
-		if !last && padding > length {
-			buf.Write(spaces[:padding-length])
-		}
+		pending = padding - length
 	}
-	var n = 0
-	var nAttrs = len(h.attrs) + r.NumAttrs()
 	for _, attr := range h.attrs {
-		writeAttr(attr, n == nAttrs-1)
-		n++
+		writeAttr("", attr)
 	}
 	r.Attrs(func(attr slog.Attr) bool {
-		writeAttr(attr, n == nAttrs-1)
-		n++
+		writeAttr("", attr)
 		return true
 	})
 	buf.WriteByte('\n')
+	return blocks
 }
 
-// FormatSlogValue formats a slog.Value for serialization to terminal.
+// FormatSlogValue formats a slog.Value for serialization to terminal,
+// with the default number formatting.
 func FormatSlogValue(v slog.Value, tmp []byte) (result []byte) {
-	var value any
-	defer func() {
-		if err := recover(); err != nil {
-			if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
-				result = []byte("<nil>")
-			} else {
-				panic(err)
-			}
-		}
-	}()
+	return defaultFormatConfig.FormatSlogValue(v, tmp)
+}
 
+// FormatSlogValue formats a slog.Value for serialization to terminal,
+// with the number formatting of the config.
+// Primitive values, durations, times, *big.Int and 256-bit integers are formatted without allocating,
+// if tmp has enough capacity.
+func (cfg *FormatConfig) FormatSlogValue(v slog.Value, tmp []byte) (result []byte) {
+	if v.Kind() == slog.KindLogValuer {
+		// encoded in place, instead of resolving to a string
+		switch x := v.Any().(type) {
+		case hexBytes:
+			return x.appendHex(tmp)
+		case hexHash:
+			return hexBytes(x[:]).appendHex(tmp)
+		}
+	}
+	v = v.Resolve()
 	switch v.Kind() {
 	case slog.KindString:
 		return appendEscapeString(tmp, v.String())
 	case slog.KindInt64: // All int-types (int8, int16 etc) wind up here
-		return appendInt64(tmp, v.Int64())
+		return appendInt64(tmp, v.Int64(), cfg.ThousandSeparator)
 	case slog.KindUint64: // All uint-types (uint8, uint16 etc) wind up here
-		return appendUint64(tmp, v.Uint64(), false)
+		return appendUint64(tmp, v.Uint64(), false, cfg.ThousandSeparator)
 	case slog.KindFloat64:
-		return strconv.AppendFloat(tmp, v.Float64(), floatFormat, 3, 64)
+		return cfg.appendFloat(tmp, v.Float64())
 	case slog.KindBool:
 		return strconv.AppendBool(tmp, v.Bool())
 	case slog.KindDuration:
-		value = v.Duration()
+		var text [32]byte
+		return appendEscapeString(tmp, string(appendDuration(text[:0], v.Duration())))
 	case slog.KindTime:
 		// Performance optimization: No need for escaping since the provided
 		// timeFormat doesn't have any escape characters, and escaping is
 		// expensive.
 		return v.Time().AppendFormat(tmp, timeFormat)
-	default:
-		value = v.Any()
 	}
-	if value == nil {
-		return []byte("<nil>")
+	if cfg.NestedFormat == NestedJSON {
+		if nv, ok := cfg.nestedValue(v); ok {
+			return appendJSONString(tmp, nv.String())
+		}
 	}
-	switch v := value.(type) {
+	value := v.Any()
+	switch x := value.(type) {
+	case nil:
+		return append(tmp, "<nil>"...)
+	case []byte:
+		if cfg.HexBytes {
+			tmp = append(tmp, "0x"...)
+			return hex.AppendEncode(tmp, x)
+		}
 	case *big.Int: // Need to be before fmt.Stringer-clause
-		return appendBigInt(tmp, v)
+		if x == nil {
+			return append(tmp, "<nil>"...)
+		}
+		return appendBigInt(tmp, x, cfg.ThousandSeparator)
+	case u256, error, TerminalStringer, fmt.Stringer:
+		// Typed nil pointers cannot be formatted with their methods.
+		if isNilPointer(x) {
+			return append(tmp, "<nil>"...)
+		}
+	}
+	switch x := value.(type) {
 	case u256: // Need to be before fmt.Stringer-clause
-		return appendU256(tmp, v)
+		return appendU256(tmp, x, cfg.ThousandSeparator)
 	case error:
-		return appendEscapeString(tmp, v.Error())
+		return appendEscapeString(tmp, x.Error())
 	case TerminalStringer:
-		return appendEscapeString(tmp, v.TerminalString())
+		return appendEscapeString(tmp, x.TerminalString())
 	case fmt.Stringer:
-		return appendEscapeString(tmp, v.String())
+		return appendEscapeString(tmp, x.String())
 	}
 
 	// We can use the 'tmp' as a scratch-buffer, to first format the
-	// value, and in a second step do escaping.
+	// value, and in a second step do escaping, if necessary.
 	internal := fmt.Appendf(tmp, "%+v", value)
-	return appendEscapeString(tmp, string(internal))
+	if !needsEscape(internal[len(tmp):]) {
+		return internal
+	}
+	return appendEscapeString(tmp, string(internal[len(tmp):]))
+}
+
+// appendFloat formats f with the configured precision,
+// switching to scientific notation outside the configured magnitudes.
+func (cfg *FormatConfig) appendFloat(dst []byte, f float64) []byte {
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && !math.IsInf(f, 0) {
+		if (cfg.FloatSciAbove > 0 && abs >= cfg.FloatSciAbove) ||
+			(cfg.FloatSciBelow > 0 && abs < cfg.FloatSciBelow) {
+			format = 'e'
+		}
+	}
+	return strconv.AppendFloat(dst, f, format, cfg.FloatPrecision, 64)
 }
 
 // appendInt64 formats n with thousand separators and writes into buffer dst.
-func appendInt64(dst []byte, n int64) []byte {
+// A zero sep disables the separators.
+func appendInt64(dst []byte, n int64, sep byte) []byte {
 	if n < 0 {
-		return appendUint64(dst, uint64(-n), true)
+		return appendUint64(dst, uint64(-n), true, sep)
 	}
-	return appendUint64(dst, uint64(n), false)
+	return appendUint64(dst, uint64(n), false, sep)
 }
 
 // appendUint64 formats n with thousand separators and writes into buffer dst.
-func appendUint64(dst []byte, n uint64, neg bool) []byte {
-	// Small numbers are fine as is
-	if n < 100000 {
-		if neg {
-			return strconv.AppendInt(dst, -int64(n), 10)
-		} else {
-			return strconv.AppendInt(dst, int64(n), 10)
-		}
+// A zero sep disables the separators.
+func appendUint64(dst []byte, n uint64, neg bool, sep byte) []byte {
+	var digits [20]byte
+	return appendDigits(dst, strconv.AppendUint(digits[:0], n, 10), neg, sep)
+}
+
+// appendDigits writes the decimal digits, with thousand separators, into buffer dst.
+// Small numbers, of up to 5 digits, and a zero sep are written without separators.
+func appendDigits(dst []byte, digits []byte, neg bool, sep byte) []byte {
+	if neg {
+		dst = append(dst, '-')
+	}
+	if sep == 0 || len(digits) <= 5 {
+		return append(dst, digits...)
+	}
+	first := len(digits) % 3
+	if first == 0 {
+		first = 3
 	}
-	// Large numbers should be split
-	const maxLength = 26
+	dst = append(dst, digits[:first]...)
+	for i := first; i < len(digits); i += 3 {
+		dst = append(dst, sep)
+		dst = append(dst, digits[i:i+3]...)
+	}
+	return dst
+}
 
-	var (
-		out   = make([]byte, maxLength)
-		i     = maxLength - 1
-		comma = 0
-	)
-	for ; n > 0; i-- {
-		if comma == 3 {
-			comma = 0
-			out[i] = '_' // "_" instead of "," for python use
-		} else {
-			comma++
-			out[i] = '0' + byte(n%10)
-			n /= 10
+// appendUint256 formats the 256-bit number, of little-endian 64-bit words,
+// with thousand separators, and writes into buffer dst.
+func appendUint256(dst []byte, words [4]uint64, neg bool, sep byte) []byte {
+	const chunk, chunkDigits = 1e19, 19
+	var digits [78]byte // 2**256 has 78 decimal digits
+	i := len(digits)
+	for words[1]|words[2]|words[3] != 0 {
+		var rem uint64
+		for j := 3; j >= 0; j-- {
+			words[j], rem = bits.Div64(rem, words[j], chunk)
+		}
+		for k := 0; k < chunkDigits; k++ {
+			i--
+			digits[i] = '0' + byte(rem%10)
+			rem /= 10
 		}
 	}
-	if neg {
-		out[i] = '-'
-		i--
-	}
-	return append(dst, out[i+1:]...)
+	// The most significant digits, without padding. Non-zero if there were more chunks.
+	var head [20]byte
+	h := strconv.AppendUint(head[:0], words[0], 10)
+	i -= len(h)
+	copy(digits[i:], h)
+	return appendDigits(dst, digits[i:], neg, sep)
 }
 
 // FormatLogfmtUint64 formats n with thousand separators.
 func FormatLogfmtUint64(n uint64) string {
-	return string(appendUint64(nil, n, false))
+	return string(appendUint64(nil, n, false, defaultFormatConfig.ThousandSeparator))
 }
 
 // appendBigInt formats n with thousand separators and writes to dst.
-func appendBigInt(dst []byte, n *big.Int) []byte {
+// A zero sep disables the separators.
+func appendBigInt(dst []byte, n *big.Int, sep byte) []byte {
 	if n.IsUint64() {
-		return appendUint64(dst, n.Uint64(), false)
+		return appendUint64(dst, n.Uint64(), false, sep)
 	}
 	if n.IsInt64() {
-		return appendInt64(dst, n.Int64())
+		return appendInt64(dst, n.Int64(), sep)
 	}
-
-	var (
-		text  = n.String()
-		buf   = make([]byte, len(text)+len(text)/3)
-		comma = 0
-		i     = len(buf) - 1
-	)
-	for j := len(text) - 1; j >= 0; j, i = j-1, i-1 {
-		c := text[j]
-
-		switch {
-		case c == '-':
-			buf[i] = c
-		case comma == 3:
-			buf[i] = '_' // "_" instead of "," for python use
-			i--
-			comma = 0
-			fallthrough
-		default:
-			buf[i] = c
-			comma++
+	if n.BitLen() <= 256 {
+		var words [4]uint64
+		for i, w := range n.Bits() {
+			if bits.UintSize == 32 {
+				words[i/2] |= uint64(w) << (32 * (i % 2))
+			} else {
+				words[i] = uint64(w)
+			}
 		}
+		return appendUint256(dst, words, n.Sign() < 0, sep)
 	}
-	return append(dst, buf[i+1:]...)
+	text := n.Append(nil, 10)
+	neg := text[0] == '-'
+	if neg {
+		text = text[1:]
+	}
+	return appendDigits(dst, text, neg, sep)
 }
 
 type u256 interface {
@@ -295,13 +463,30 @@ type u256 interface {
 	Dec() string
 }
 
+// u256Bytes is implemented by 256-bit integer types that can be formatted without allocating,
+// like github.com/holiman/uint256.Int.
+type u256Bytes interface {
+	Bytes32() [32]byte
+}
+
 // appendU256 formats n with thousand separators.
-func appendU256(dst []byte, n u256) []byte {
+// A zero sep disables the separators.
+func appendU256(dst []byte, n u256, sep byte) []byte {
 	if n.IsUint64() {
-		return appendUint64(dst, n.Uint64(), false)
+		return appendUint64(dst, n.Uint64(), false, sep)
 	}
-	res := []byte(n.PrettyDec('_')) // "_" instead of "," for python use
-	return append(dst, res...)
+	if b, ok := n.(u256Bytes); ok {
+		data := b.Bytes32()
+		var words [4]uint64
+		for i := range words {
+			words[i] = binary.BigEndian.Uint64(data[24-i*8:])
+		}
+		return appendUint256(dst, words, false, sep)
+	}
+	if sep == 0 {
+		return append(dst, n.Dec()...)
+	}
+	return append(dst, n.PrettyDec(sep)...)
 }
 
 // appendEscapeString writes the string s to the given writer, with
@@ -330,10 +515,29 @@ func appendEscapeString(dst []byte, s string) []byte {
 	// it contained a space
 	if needsQuoting {
 		dst = append(dst, '"')
-		dst = append(dst, []byte(s)...)
+		dst = append(dst, s...)
 		return append(dst, '"')
 	}
-	return append(dst, []byte(s)...)
+	return append(dst, s...)
+}
+
+// needsEscape checks if appendEscapeString would quote or escape the text.
+func needsEscape(text []byte) bool {
+	for _, r := range string(text) {
+		if r <= '"' || r > '~' || r == '=' {
+			return true
+		}
+	}
+	return false
+}
+
+// appendJSONString writes the compact JSON string s as-is,
+// or escaped and quoted if it contains spaces or line-breaks.
+func appendJSONString(dst []byte, s string) []byte {
+	if strings.ContainsAny(s, " \r\n\t") {
+		return strconv.AppendQuote(dst, s)
+	}
+	return append(dst, s...)
 }
 
 // escapeMessage checks if the provided string needs escaping/quoting, similarly
@@ -400,3 +604,109 @@ func writePosIntWidth(b *bytes.Buffer, i, width int) {
 	bb[bp] = byte('0' + i)
 	b.Write(bb[bp:])
 }
+
+// appendDuration writes the same text as time.Duration.String, without allocating.
+// Ported from the time package.
+func appendDuration(dst []byte, d time.Duration) []byte {
+	// Largest time is 2540400h10m10.000000000s
+	var buf [32]byte
+	w := len(buf)
+
+	u := uint64(d)
+	neg := d < 0
+	if neg {
+		u = -u
+	}
+
+	if u < uint64(time.Second) {
+		// Special case: if duration is smaller than a second,
+		// use smaller units, like 1.2ms
+		var prec int
+		w--
+		buf[w] = 's'
+		w--
+		switch {
+		case u == 0:
+			return append(dst, "0s"...)
+		case u < uint64(time.Microsecond):
+			prec = 0
+			buf[w] = 'n'
+		case u < uint64(time.Millisecond):
+			prec = 3
+			// U+00B5 'µ' micro sign == 0xC2 0xB5
+			w-- // Need room for two bytes.
+			copy(buf[w:], "µ")
+		default:
+			prec = 6
+			buf[w] = 'm'
+		}
+		w, u = fmtFrac(buf[:w], u, prec)
+		w = fmtInt(buf[:w], u)
+	} else {
+		w--
+		buf[w] = 's'
+		w, u = fmtFrac(buf[:w], u, 9)
+		// u is now integer seconds
+		w = fmtInt(buf[:w], u%60)
+		u /= 60
+		// u is now integer minutes
+		if u > 0 {
+			w--
+			buf[w] = 'm'
+			w = fmtInt(buf[:w], u%60)
+			u /= 60
+			// u is now integer hours
+			if u > 0 {
+				w--
+				buf[w] = 'h'
+				w = fmtInt(buf[:w], u)
+			}
+		}
+	}
+	if neg {
+		w--
+		buf[w] = '-'
+	}
+	return append(dst, buf[w:]...)
+}
+
+// fmtFrac formats the fraction of v/10**prec (e.g., ".12345") into the
+// tail of buf, omitting trailing zeros. It omits the decimal
+// point too when the fraction is 0. It returns the index where the
+// output bytes begin and the value v/10**prec.
+func fmtFrac(buf []byte, v uint64, prec int) (nw int, nv uint64) {
+	// Omit trailing zeros up to and including decimal point.
+	w := len(buf)
+	print := false
+	for i := 0; i < prec; i++ {
+		digit := v % 10
+		print = print || digit != 0
+		if print {
+			w--
+			buf[w] = byte(digit) + '0'
+		}
+		v /= 10
+	}
+	if print {
+		w--
+		buf[w] = '.'
+	}
+	return w, v
+}
+
+// fmtInt formats v into the tail of buf.
+// It returns the index where the output begins.
+func fmtInt(buf []byte, v uint64) int {
+	w := len(buf)
+	if v == 0 {
+		w--
+		buf[w] = '0'
+	} else {
+		for v > 0 {
+			w--
+			buf[w] = byte(v%10) + '0'
+			v /= 10
+		}
+	}
+	return w
+}
@@ -0,0 +1,295 @@
+package log
+
+import (
+	"bytes"
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// NestedFormat selects how maps, slices, structs and groups are rendered
+// by the TerminalHandler and LogfmtHandler.
+type NestedFormat uint8
+
+const (
+	// NestedDefault renders nested values with the Go %+v formatting.
+	NestedDefault NestedFormat = iota
+	// NestedFlatten renders nested values as separate attributes with dotted keys,
+	// e.g. example.hello=123, and empty nested values as example={} or example=[]
+	NestedFlatten
+	// NestedJSON renders nested values as compact inline JSON,
+	// e.g. example={"hello":123}
+	NestedJSON
+)
+
+const (
+	defaultNestedMaxDepth = 4
+	defaultNestedMaxLen   = 20
+	// nestedTruncatedKey is the key that holds the number of omitted entries, when exceeding the max length.
+	nestedTruncatedKey = "_truncated"
+	// nestedEllipsis replaces values that are nested deeper than the max depth, and marks truncated arrays.
+	nestedEllipsis = "..."
+)
+
+func (cfg *FormatConfig) nestedMaxDepth() int {
+	if cfg.NestedMaxDepth <= 0 {
+		return defaultNestedMaxDepth
+	}
+	return cfg.NestedMaxDepth
+}
+
+func (cfg *FormatConfig) nestedMaxLen() int {
+	if cfg.NestedMaxLen <= 0 {
+		return defaultNestedMaxLen
+	}
+	return cfg.NestedMaxLen
+}
+
+// nestedValue converts v into the configured nested format:
+// a group value for NestedFlatten, or a JSON string value for NestedJSON.
+// This returns ok=false if v is not a nested value, or nested formatting is not enabled.
+func (cfg *FormatConfig) nestedValue(v slog.Value) (out slog.Value, ok bool) {
+	rv, ok := nestedReflect(v)
+	if !ok {
+		return slog.Value{}, false
+	}
+	switch cfg.NestedFormat {
+	case NestedFlatten:
+		return cfg.flattenValue(rv, 1), true
+	case NestedJSON:
+		var buf bytes.Buffer
+		cfg.writeNestedJSON(&buf, rv, 1)
+		return slog.StringValue(buf.String()), true
+	default:
+		return slog.Value{}, false
+	}
+}
+
+// nestedReflect returns the reflected value of v, if v can be expanded as nested value.
+// Values with their own text representation, like errors and stringers, are not expanded.
+func nestedReflect(v slog.Value) (reflect.Value, bool) {
+	v = v.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		return reflect.ValueOf(v.Group()), true
+	case slog.KindAny:
+	default:
+		return reflect.Value{}, false
+	}
+	x := v.Any()
+	switch x.(type) {
+	case nil, error, fmt.Stringer, TerminalStringer, json.Marshaler, encoding.TextMarshaler, []byte:
+		return reflect.Value{}, false
+	}
+	rv := reflect.ValueOf(x)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		return rv, true
+	default:
+		return reflect.Value{}, false
+	}
+}
+
+// nestedEntries calls fn on each key/value entry of the nested value, up to maxLen entries.
+// Map entries are sorted by key, for deterministic output.
+// The number of omitted entries is returned.
+func nestedEntries(rv reflect.Value, maxLen int, fn func(key string, v slog.Value)) (omitted int) {
+	if attrs, ok := rv.Interface().([]slog.Attr); ok {
+		for i, a := range attrs {
+			if i >= maxLen {
+				return len(attrs) - i
+			}
+			fn(a.Key, a.Value)
+		}
+		return 0
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		type entry struct {
+			key string
+			v   reflect.Value
+		}
+		entries := make([]entry, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			entries = append(entries, entry{key: fmt.Sprint(iter.Key().Interface()), v: iter.Value()})
+		}
+		slices.SortFunc(entries, func(a, b entry) int {
+			return cmp.Compare(a.key, b.key)
+		})
+		for i, e := range entries {
+			if i >= maxLen {
+				return len(entries) - i
+			}
+			fn(e.key, slog.AnyValue(e.v.Interface()))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if i >= maxLen {
+				return rv.Len() - i
+			}
+			fn(strconv.Itoa(i), slog.AnyValue(rv.Index(i).Interface()))
+		}
+	case reflect.Struct:
+		t := rv.Type()
+		n := 0
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			key := f.Name
+			if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag == "-" {
+				continue
+			} else if tag != "" {
+				key = tag
+			}
+			n++
+			if n > maxLen {
+				omitted++
+				continue
+			}
+			fn(key, slog.AnyValue(rv.Field(i).Interface()))
+		}
+	}
+	return omitted
+}
+
+// flattenNested converts the nested value into a list of attributes,
+// with nested values converted to groups, up to the max depth.
+// flattenValue returns the flattened nested value as group value.
+// Empty values have no attributes to flatten into, and are rendered as {} or [] string instead.
+func (cfg *FormatConfig) flattenValue(rv reflect.Value, depth int) slog.Value {
+	if attrs := cfg.flattenNested(rv, depth); len(attrs) > 0 {
+		return slog.GroupValue(attrs...)
+	}
+	if _, isGroup := rv.Interface().([]slog.Attr); !isGroup && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) {
+		return slog.StringValue("[]")
+	}
+	return slog.StringValue("{}")
+}
+
+func (cfg *FormatConfig) flattenNested(rv reflect.Value, depth int) []slog.Attr {
+	var attrs []slog.Attr
+	omitted := nestedEntries(rv, cfg.nestedMaxLen(), func(key string, v slog.Value) {
+		if inner, ok := nestedReflect(v); ok {
+			if depth >= cfg.nestedMaxDepth() {
+				attrs = append(attrs, slog.String(key, nestedEllipsis))
+			} else {
+				attrs = append(attrs, slog.Attr{Key: key, Value: cfg.flattenValue(inner, depth+1)})
+			}
+			return
+		}
+		attrs = append(attrs, slog.Attr{Key: key, Value: v})
+	})
+	if omitted > 0 {
+		attrs = append(attrs, slog.Int(nestedTruncatedKey, omitted))
+	}
+	return attrs
+}
+
+// writeNestedJSON writes the nested value as compact JSON, up to the max depth.
+func (cfg *FormatConfig) writeNestedJSON(buf *bytes.Buffer, rv reflect.Value, depth int) {
+	_, isGroup := rv.Interface().([]slog.Attr)
+	isArray := !isGroup && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array)
+	if isArray {
+		buf.WriteByte('[')
+	} else {
+		buf.WriteByte('{')
+	}
+	n := 0
+	omitted := nestedEntries(rv, cfg.nestedMaxLen(), func(key string, v slog.Value) {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		n++
+		if !isArray {
+			writeJSONString(buf, key)
+			buf.WriteByte(':')
+		}
+		if inner, ok := nestedReflect(v); ok {
+			if depth >= cfg.nestedMaxDepth() {
+				writeJSONString(buf, nestedEllipsis)
+			} else {
+				cfg.writeNestedJSON(buf, inner, depth+1)
+			}
+			return
+		}
+		writeJSONLeaf(buf, v)
+	})
+	if omitted > 0 {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		if isArray {
+			writeJSONString(buf, nestedEllipsis+"+"+strconv.Itoa(omitted))
+		} else {
+			writeJSONString(buf, nestedTruncatedKey)
+			buf.WriteByte(':')
+			buf.WriteString(strconv.Itoa(omitted))
+		}
+	}
+	if isArray {
+		buf.WriteByte(']')
+	} else {
+		buf.WriteByte('}')
+	}
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	data, _ := json.Marshal(s) // strings always encode
+	buf.Write(data)
+}
+
+// writeJSONLeaf writes a non-nested value as JSON.
+// Values that cannot be encoded as JSON are written as string.
+func writeJSONLeaf(buf *bytes.Buffer, v slog.Value) {
+	var x any
+	switch v.Kind() {
+	case slog.KindDuration:
+		x = v.Duration().String()
+	case slog.KindTime:
+		x = v.Time().Format(timeFormat)
+	default:
+		x = v.Any()
+	}
+	if isNilPointer(x) {
+		buf.WriteString("null")
+		return
+	}
+	switch t := x.(type) {
+	case error:
+		x = t.Error()
+	case TerminalStringer:
+		x = t.TerminalString()
+	}
+	data, err := json.Marshal(x)
+	if err != nil {
+		writeJSONString(buf, fmt.Sprintf("%+v", x))
+		return
+	}
+	buf.Write(data)
+}
+
+// isNilPointer checks if x is a nil pointer, or nil value of another nillable kind, wrapped in a non-nil interface.
+func isNilPointer(x any) bool {
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
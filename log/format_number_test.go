@@ -0,0 +1,79 @@
+package log
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// testU256 mimics the methods of a 256-bit integer type like github.com/holiman/uint256.Int,
+// with little-endian 64-bit words.
+type testU256 [4]uint64
+
+func (n *testU256) IsUint64() bool { return n[1]|n[2]|n[3] == 0 }
+func (n *testU256) Uint64() uint64 { return n[0] }
+func (n *testU256) Dec() string {
+	b := n.Bytes32()
+	return new(big.Int).SetBytes(b[:]).String()
+}
+func (n *testU256) PrettyDec(sep byte) string {
+	return string(appendDigits(nil, []byte(n.Dec()), false, sep))
+}
+func (n *testU256) Bytes32() (out [32]byte) {
+	for i, w := range n {
+		binary.BigEndian.PutUint64(out[24-i*8:], w)
+	}
+	return out
+}
+
+// testU256Dec is a 256-bit integer type that can only be formatted through its methods.
+type testU256Dec struct{ testU256 }
+
+func (n *testU256Dec) Bytes32() {}
+
+func TestAppendNumbers(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	for i := 0; i < 1000; i++ {
+		var words testU256
+		for j := range words {
+			if rng.Intn(3) > 0 {
+				words[j] = rng.Uint64()
+			}
+		}
+		b := words.Bytes32()
+		n := new(big.Int).SetBytes(b[:])
+		if rng.Intn(2) == 0 {
+			n.Neg(n)
+		}
+		// > 256 bits
+		large := new(big.Int).Lsh(n, uint(rng.Intn(300)))
+
+		for _, sep := range []byte{0, '_'} {
+			for _, x := range []*big.Int{n, large} {
+				abs := new(big.Int).Abs(x)
+				want := string(appendDigits(nil, []byte(abs.Text(10)), x.Sign() < 0, sep))
+				if got := string(appendBigInt(nil, x, sep)); got != want {
+					t.Fatalf("big.Int %s: got %s, want %s", x, got, want)
+				}
+			}
+			want := words.PrettyDec(sep)
+			if got := string(appendU256(nil, &words, sep)); got != want {
+				t.Fatalf("u256 %s: got %s, want %s", words.Dec(), got, want)
+			}
+			if got := string(appendU256(nil, &testU256Dec{words}, sep)); got != want {
+				t.Fatalf("u256 by methods %s: got %s, want %s", words.Dec(), got, want)
+			}
+		}
+	}
+}
+
+func TestAppendDuration(t *testing.T) {
+	for _, d := range []time.Duration{0, 1, -1, 999, time.Microsecond + 500, 1234 * time.Millisecond,
+		-90 * time.Minute, 1<<63 - 1, -1 << 63} {
+		if got := string(appendDuration(nil, d)); got != d.String() {
+			t.Errorf("got %s, want %s", got, d)
+		}
+	}
+}
@@ -5,12 +5,78 @@ type FormatConfig struct {
 	// UseColor formats the output with color-codes.
 	// No-op in handlers where color is not supported.
 	UseColor bool
+	// AutoColor enables UseColor if the output supports it, see DetectColorSupport.
+	// No-op in handlers where color is not supported.
+	AutoColor bool
+	// Theme configures the colors. If nil, a builtin theme is used.
+	Theme *ColorTheme
 	// IncludeSource shows the file path and line number of the log source
 	IncludeSource bool
 	// ExcludeTime shows the date / time
 	ExcludeTime bool
 	// SourceRelDir is the dir to resolve sources to as relative files
 	SourceRelDir string
+	// SourcePath selects how the file path of the source is rendered.
+	SourcePath SourcePath
+	// SourceModule is the module path that is trimmed from source paths, with SourcePathModule.
+	// If empty, the main module of the binary is trimmed.
+	SourceModule string
+	// SourceFunc includes the function name of the source, qualified by package name.
+	// The JSONHandler always includes the function, fully qualified by package path unless SourceFunc is set.
+	SourceFunc bool
+	// SourceMaxLen shortens source paths that are longer, by eliding middle directories, e.g. p2p/…/peer.go.
+	// Zero does not shorten source paths.
+	SourceMaxLen int
+	// SourcePadding is the width the source info is right-padded to.
+	// Only supported by the TerminalHandler, which defaults to 25.
+	SourcePadding int
+
+	// ThousandSeparator is placed between every 3 digits of large integers,
+	// including *big.Int and uint256 values. Zero disables the separators.
+	ThousandSeparator byte
+	// FloatPrecision is the number of decimals of formatted floats.
+	// A negative precision formats the shortest representation that round-trips.
+	FloatPrecision int
+	// FloatSciAbove is the magnitude from which floats are formatted in scientific notation.
+	// Zero disables scientific notation for large floats.
+	FloatSciAbove float64
+	// FloatSciBelow is the magnitude below which non-zero floats are formatted in scientific notation.
+	// Zero disables scientific notation for small floats.
+	FloatSciBelow float64
+	// HexBytes formats []byte values as 0x-prefixed hex string, instead of a list of numbers.
+	// Only supported by the TerminalHandler.
+	HexBytes bool
+
+	// FieldWidths is a schema of fixed column-widths for known attribute keys.
+	// Other keys are padded to the longest value seen so far.
+	// Only supported by the TerminalHandler.
+	FieldWidths map[string]int
+	// SharedState is shared with other TerminalHandlers that write to the same output,
+	// to not interleave their writes, and to align their attributes.
+	// If nil, a new state is used, which is shared with the derived handlers only.
+	// Only supported by the TerminalHandler.
+	SharedState *TerminalState
+
+	// MultiLine renders multi-line messages and attribute values as indented blocks below the record line,
+	// instead of escaping the line-breaks. Only supported by the TerminalHandler.
+	MultiLine bool
+
+	// NestedFormat selects how maps, slices, structs and groups are rendered.
+	// Not applicable to the JSONHandler, which always renders nested values as JSON.
+	NestedFormat NestedFormat
+	// NestedMaxDepth limits how deep nested values are rendered. Zero for the default of 4.
+	NestedMaxDepth int
+	// NestedMaxLen limits how many entries of a nested value are rendered. Zero for the default of 20.
+	NestedMaxLen int
+
+	// recordTime caches the formatted record time, if not nil.
+	recordTime *timeCache
+}
+
+// defaultFormatConfig is used when formatting values outside a handler.
+var defaultFormatConfig = FormatConfig{
+	ThousandSeparator: '_', // "_" instead of "," for python use
+	FloatPrecision:    3,
 }
 
 func (cfg *FormatConfig) Apply(opts ...FormatOption) {
@@ -28,6 +94,21 @@ func WithColor(useColor bool) FormatOption {
 	}
 }
 
+// WithAutoColor sets FormatConfig.AutoColor,
+// to detect color support of the output, and honor the NO_COLOR and FORCE_COLOR conventions.
+func WithAutoColor() FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.AutoColor = true
+	}
+}
+
+// WithTheme sets FormatConfig.Theme
+func WithTheme(theme *ColorTheme) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.Theme = theme
+	}
+}
+
 // WithIncludeSource sets FormatConfig.IncludeSource
 func WithIncludeSource(includeSource bool) FormatOption {
 	return func(cfg *FormatConfig) {
@@ -48,3 +129,107 @@ func WithSourceRelDir(dir string) FormatOption {
 		cfg.SourceRelDir = dir
 	}
 }
+
+// WithSourcePath sets FormatConfig.SourcePath
+func WithSourcePath(mode SourcePath) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.SourcePath = mode
+	}
+}
+
+// WithSourceModule sets FormatConfig.SourceModule, and FormatConfig.SourcePath to SourcePathModule
+func WithSourceModule(module string) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.SourcePath = SourcePathModule
+		cfg.SourceModule = module
+	}
+}
+
+// WithSourceFunc sets FormatConfig.SourceFunc
+func WithSourceFunc(includeFunc bool) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.SourceFunc = includeFunc
+	}
+}
+
+// WithSourceMaxLen sets FormatConfig.SourceMaxLen
+func WithSourceMaxLen(maxLen int) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.SourceMaxLen = maxLen
+	}
+}
+
+// WithSourcePadding sets FormatConfig.SourcePadding.
+// Use 0 to not pad the source info.
+func WithSourcePadding(width int) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.SourcePadding = width
+	}
+}
+
+// WithThousandSeparator sets FormatConfig.ThousandSeparator.
+// Use 0 to format integers at full width, without separators.
+func WithThousandSeparator(sep byte) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.ThousandSeparator = sep
+	}
+}
+
+// WithFloatPrecision sets FormatConfig.FloatPrecision.
+// Use -1 to format floats in the shortest representation.
+func WithFloatPrecision(prec int) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.FloatPrecision = prec
+	}
+}
+
+// WithFloatScientific sets FormatConfig.FloatSciBelow and FormatConfig.FloatSciAbove
+func WithFloatScientific(below, above float64) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.FloatSciBelow = below
+		cfg.FloatSciAbove = above
+	}
+}
+
+// WithNestedFormat sets FormatConfig.NestedFormat
+func WithNestedFormat(format NestedFormat) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.NestedFormat = format
+	}
+}
+
+// WithNestedLimits sets FormatConfig.NestedMaxDepth and FormatConfig.NestedMaxLen
+func WithNestedLimits(maxDepth, maxLen int) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.NestedMaxDepth = maxDepth
+		cfg.NestedMaxLen = maxLen
+	}
+}
+
+// WithHexBytes sets FormatConfig.HexBytes
+func WithHexBytes(hexBytes bool) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.HexBytes = hexBytes
+	}
+}
+
+// WithFieldWidths sets FormatConfig.FieldWidths
+func WithFieldWidths(widths map[string]int) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.FieldWidths = widths
+	}
+}
+
+// WithSharedState sets FormatConfig.SharedState
+func WithSharedState(state *TerminalState) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.SharedState = state
+	}
+}
+
+// WithMultiLine sets FormatConfig.MultiLine
+func WithMultiLine(multiLine bool) FormatOption {
+	return func(cfg *FormatConfig) {
+		cfg.MultiLine = multiLine
+	}
+}
@@ -0,0 +1,204 @@
+package log
+
+import (
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// SourcePath selects how the file path of the log source is rendered.
+type SourcePath uint8
+
+const (
+	// SourcePathRel renders the path relative to FormatConfig.SourceRelDir.
+	// If that is not possible, the GOPATH or module cache directory is trimmed from the path,
+	// e.g. github.com/foo/bar@v1.2.3/p2p/peer.go
+	SourcePathRel SourcePath = iota
+	// SourcePathModule renders the path as package import path and file name,
+	// independent of where the source was built, with the module path trimmed,
+	// e.g. p2p/peer.go for the file of package github.com/foo/bar/p2p in module github.com/foo/bar.
+	SourcePathModule
+	// SourcePathBase renders only the file name, e.g. peer.go
+	SourcePathBase
+)
+
+const (
+	// defaultSourcePadding is the default width the TerminalHandler pads the source info to.
+	defaultSourcePadding = 25
+	// sourceFuncKey is the attribute key of the source function, like in the slog source group.
+	sourceFuncKey = "function"
+	// sourceEllipsis replaces the directories that are elided from long source paths.
+	sourceEllipsis = "…"
+)
+
+// sourceFile returns the file path of the source, trimmed and shortened as configured.
+func (cfg *FormatConfig) sourceFile(s *slog.Source) string {
+	var file string
+	switch cfg.SourcePath {
+	case SourcePathModule:
+		file = cfg.modulePath(s)
+	case SourcePathBase:
+		file = path.Base(filepath.ToSlash(s.File))
+	default:
+		file = trimBuildPath(s.File)
+		if cfg.SourceRelDir != "" {
+			if rel, err := filepath.Rel(cfg.SourceRelDir, s.File); err == nil {
+				file = rel
+			}
+		}
+	}
+	return shortenPath(file, cfg.SourceMaxLen)
+}
+
+// sourceString returns the "file:line" source info, followed by the function name if enabled.
+func (cfg *FormatConfig) sourceString(s *slog.Source) string {
+	out := cfg.sourceFile(s) + ":" + strconv.Itoa(s.Line)
+	if cfg.SourceFunc && s.Function != "" {
+		out += " " + shortFuncName(s.Function)
+	}
+	return out
+}
+
+// sourceAttr returns the source attribute, as rendered by the JSON and logfmt handlers.
+func (cfg *FormatConfig) sourceAttr(s *slog.Source, logfmt bool) slog.Attr {
+	file := cfg.sourceFile(s)
+	if !logfmt {
+		// like the slog source group, which includes the full function name
+		var attrs []slog.Attr
+		if s.Function != "" {
+			fn := s.Function
+			if cfg.SourceFunc {
+				fn = shortFuncName(fn)
+			}
+			attrs = append(attrs, slog.String(sourceFuncKey, fn))
+		}
+		attrs = append(attrs, slog.String("file", file), slog.Int("line", s.Line))
+		return slog.Attr{Key: slog.SourceKey, Value: slog.GroupValue(attrs...)}
+	}
+	src := slog.String(slog.SourceKey, file+":"+strconv.Itoa(s.Line))
+	if !cfg.SourceFunc || s.Function == "" {
+		return src
+	}
+	// an inline group, to render the function as separate attribute
+	return slog.Attr{Value: slog.GroupValue(src, slog.String(sourceFuncKey, shortFuncName(s.Function)))}
+}
+
+// modulePath returns the package import path and file name of the source, with the module path trimmed.
+func (cfg *FormatConfig) modulePath(s *slog.Source) string {
+	pkg := strings.TrimSuffix(funcPackage(s.Function), "_test")
+	if pkg == "" || pkg == "main" {
+		pkg = buildInfo().mainPkg
+		if pkg == "" {
+			return trimBuildPath(s.File)
+		}
+	}
+	p := pkg + "/" + path.Base(filepath.ToSlash(s.File))
+	module := cfg.SourceModule
+	if module == "" {
+		module = buildInfo().mainModule
+	}
+	if rest, ok := strings.CutPrefix(p, module+"/"); ok && module != "" {
+		return rest
+	}
+	return p
+}
+
+// funcPackage returns the package import path of a fully qualified function name,
+// e.g. github.com/foo/bar/p2p for github.com/foo/bar/p2p.(*Peer).run
+func funcPackage(fn string) string {
+	if i := strings.IndexByte(fn, '['); i >= 0 {
+		fn = fn[:i] // type parameters may contain other package paths
+	}
+	slash := strings.LastIndexByte(fn, '/')
+	dot := strings.IndexByte(fn[slash+1:], '.')
+	if dot < 0 {
+		return ""
+	}
+	return fn[:slash+1+dot]
+}
+
+// shortFuncName returns the function name, qualified by package name instead of the package path,
+// e.g. p2p.(*Peer).run for github.com/foo/bar/p2p.(*Peer).run
+func shortFuncName(fn string) string {
+	name := fn
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	return fn[strings.LastIndexByte(name, '/')+1:]
+}
+
+// shortenPath elides the middle directories of a path that is longer than maxLen,
+// keeping the first directory, and as many of the last directories as fit,
+// e.g. p2p/…/peer.go. The file name is always kept. Zero maxLen does not shorten the path.
+func shortenPath(p string, maxLen int) string {
+	if maxLen <= 0 || len(p) <= maxLen {
+		return p
+	}
+	p = filepath.ToSlash(p)
+	first := strings.IndexByte(p, '/')
+	last := strings.LastIndexByte(p, '/')
+	if first < 0 || first == last {
+		return p // no directories to elide
+	}
+	head, tail := p[:first+1], p[last:]
+	for {
+		i := strings.LastIndexByte(p[:len(p)-len(tail)], '/')
+		if i <= first || len(head)+utf8.RuneCountInString(sourceEllipsis)+len(p)-i > maxLen {
+			break
+		}
+		tail = p[i:]
+	}
+	return head + sourceEllipsis + tail
+}
+
+// trimBuildPath trims the module cache or GOPATH directory from the path of a source file,
+// e.g. github.com/foo/bar@v1.2.3/p2p/peer.go. Other paths are returned as-is.
+func trimBuildPath(file string) string {
+	file = filepath.ToSlash(file)
+	for _, prefix := range buildInfo().pathPrefixes {
+		if rest, ok := strings.CutPrefix(file, prefix); ok {
+			return rest
+		}
+	}
+	// the source may be built in another environment, with a module cache in the default location
+	if i := strings.LastIndex(file, "/pkg/mod/"); i >= 0 {
+		return file[i+len("/pkg/mod/"):]
+	}
+	return file
+}
+
+type sourceBuildInfo struct {
+	mainPkg      string
+	mainModule   string
+	pathPrefixes []string
+}
+
+// buildInfo is loaded once, to trim source paths.
+var buildInfo = sync.OnceValue(func() (out sourceBuildInfo) {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Path != "command-line-arguments" {
+			out.mainPkg = info.Path
+		}
+		out.mainModule = info.Main.Path
+	}
+	if modCache := os.Getenv("GOMODCACHE"); modCache != "" {
+		out.pathPrefixes = append(out.pathPrefixes, filepath.ToSlash(modCache)+"/")
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			gopath = filepath.Join(home, "go")
+		}
+	}
+	for _, dir := range filepath.SplitList(gopath) {
+		dir = filepath.ToSlash(dir)
+		out.pathPrefixes = append(out.pathPrefixes, dir+"/pkg/mod/", dir+"/src/")
+	}
+	return out
+})
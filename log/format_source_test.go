@@ -0,0 +1,82 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestShortenPath(t *testing.T) {
+	cases := []struct {
+		path   string
+		maxLen int
+		want   string
+	}{
+		{"p2p/host/peerstore/peer.go", 0, "p2p/host/peerstore/peer.go"},
+		{"p2p/host/peerstore/peer.go", 100, "p2p/host/peerstore/peer.go"},
+		{"p2p/host/peerstore/peer.go", 24, "p2p/…/peerstore/peer.go"},
+		{"p2p/host/peerstore/peer.go", 15, "p2p/…/peer.go"},
+		{"p2p/host/peerstore/peer.go", 5, "p2p/…/peer.go"},
+		{"p2p/peer.go", 5, "p2p/peer.go"},
+		{"peer.go", 3, "peer.go"},
+	}
+	for _, c := range cases {
+		if got := shortenPath(c.path, c.maxLen); got != c.want {
+			t.Errorf("shortenPath(%q, %d) = %q, expected %q", c.path, c.maxLen, got, c.want)
+		}
+	}
+}
+
+func TestFuncNames(t *testing.T) {
+	cases := []struct {
+		fn, pkg, short string
+	}{
+		{"github.com/foo/bar/p2p.(*Peer).run", "github.com/foo/bar/p2p", "p2p.(*Peer).run"},
+		{"github.com/foo/bar/p2p.run.func1", "github.com/foo/bar/p2p", "p2p.run.func1"},
+		{"main.main", "main", "main.main"},
+		{"github.com/foo/bar.Map[...]", "github.com/foo/bar", "bar.Map[...]"},
+		{"github.com/foo/bar.Map[go.shape.struct { github.com/x/y.T }]", "github.com/foo/bar", "bar.Map[go.shape.struct { github.com/x/y.T }]"},
+	}
+	for _, c := range cases {
+		if got := funcPackage(c.fn); got != c.pkg {
+			t.Errorf("funcPackage(%q) = %q, expected %q", c.fn, got, c.pkg)
+		}
+		if got := shortFuncName(c.fn); got != c.short {
+			t.Errorf("shortFuncName(%q) = %q, expected %q", c.fn, got, c.short)
+		}
+	}
+}
+
+func TestTrimBuildPath(t *testing.T) {
+	if got := trimBuildPath("/home/ci/go/pkg/mod/github.com/foo/bar@v1.2.3/p2p/peer.go"); got != "github.com/foo/bar@v1.2.3/p2p/peer.go" {
+		t.Fatalf("unexpected module cache path %q", got)
+	}
+	if got := trimBuildPath("/src/app/main.go"); got != "/src/app/main.go" {
+		t.Fatalf("expected path outside of GOPATH to be kept, got %q", got)
+	}
+	for _, prefix := range buildInfo().pathPrefixes {
+		if strings.HasSuffix(prefix, "/src/") {
+			if got := trimBuildPath(prefix + "github.com/foo/bar/peer.go"); got != "github.com/foo/bar/peer.go" {
+				t.Fatalf("unexpected GOPATH path %q", got)
+			}
+		}
+	}
+}
+
+func TestSourcePadding(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(TerminalHandler(&buf, WithExcludeTime(true), WithIncludeSource(true),
+		WithSourcePath(SourcePathBase), WithSourcePadding(50)))
+	logger.Info("hello")
+	src, _, _ := strings.Cut(buf.String(), "INFO")
+	if len(src) != 51 || !strings.HasPrefix(src, "format_source_test.go:") {
+		t.Fatalf("expected source padded to 50, got %q", src)
+	}
+	buf.Reset()
+	logger = New(TerminalHandler(&buf, WithExcludeTime(true), WithIncludeSource(true),
+		WithSourcePath(SourcePathBase), WithSourcePadding(0)))
+	logger.Info("hello")
+	if src, _, _ = strings.Cut(buf.String(), " INFO"); strings.HasSuffix(src, " ") {
+		t.Fatalf("expected unpadded source, got %q", buf.String())
+	}
+}
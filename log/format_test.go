@@ -0,0 +1,128 @@
+package log_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func TestTerminalNumberFormat(t *testing.T) {
+	big1, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	format := func(opts ...log.FormatOption) string {
+		var buf bytes.Buffer
+		opts = append(opts, log.WithExcludeTime(true))
+		logger := log.New(log.TerminalHandler(&buf, opts...))
+		logger.Info("numbers", "a", uint64(1234567), "b", -1234567, "c", big1, "f", 0.000123456)
+		return buf.String()
+	}
+	t.Run("default", func(t *testing.T) {
+		got := format()
+		assertSubstring(t, got, "a=1_234_567 ")
+		assertSubstring(t, got, "b=-1_234_567 ")
+		assertSubstring(t, got, "c=-123_456_789_012_345_678_901_234_567_890 ")
+		assertSubstring(t, got, "f=0.000\n")
+	})
+	t.Run("separator", func(t *testing.T) {
+		got := format(log.WithThousandSeparator(','))
+		assertSubstring(t, got, "a=1,234,567 ")
+		assertSubstring(t, got, "c=-123,456,789,012,345,678,901,234,567,890 ")
+	})
+	t.Run("full width", func(t *testing.T) {
+		got := format(log.WithThousandSeparator(0))
+		assertSubstring(t, got, "a=1234567 ")
+		assertSubstring(t, got, "b=-1234567 ")
+		assertSubstring(t, got, "c=-123456789012345678901234567890 ")
+	})
+	t.Run("shortest float", func(t *testing.T) {
+		got := format(log.WithFloatPrecision(-1))
+		assertSubstring(t, got, "f=0.000123456\n")
+	})
+	t.Run("scientific float", func(t *testing.T) {
+		got := format(log.WithFloatPrecision(2), log.WithFloatScientific(0.001, 1e6))
+		assertSubstring(t, got, "f=1.23e-04\n")
+	})
+}
+
+func TestNestedFormat(t *testing.T) {
+	type inner struct {
+		Name   string `json:"name"`
+		Hidden string `json:"-"`
+		Deep   map[string]map[string]int
+	}
+	value := map[string]any{
+		"s":    inner{Name: "foo", Hidden: "bar", Deep: map[string]map[string]int{"x": {"y": 1}}},
+		"list": []int{1, 2, 3, 4},
+	}
+	opts := []log.FormatOption{log.WithExcludeTime(true), log.WithNestedLimits(3, 3)}
+
+	t.Run("terminal flatten", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := log.New(log.TerminalHandler(&buf, append(opts, log.WithNestedFormat(log.NestedFlatten))...))
+		logger.Info("hello", "v", value, slog.Group("g", "a", 1))
+		got := buf.String()
+		assertSubstring(t, got, "v.list.0=1 v.list.1=2 v.list.2=3 v.list._truncated=1 ")
+		assertSubstring(t, got, "v.s.name=foo v.s.Deep.x=... g.a=1\n")
+	})
+	t.Run("logfmt flatten", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := log.New(log.LogfmtHandler(&buf, append(opts, log.WithNestedFormat(log.NestedFlatten))...))
+		logger.Info("hello", "v", value)
+		assertEqual(t, buf.String(), "lvl=info msg=hello v.list.0=1 v.list.1=2 v.list.2=3 v.list._truncated=1 v.s.name=foo v.s.Deep.x=...\n")
+	})
+	t.Run("flatten empty", func(t *testing.T) {
+		empty := []any{"m", map[string]int{}, "s", struct{}{}, "l", []int{}, "n", map[string]any{"inner": map[string]int{}}}
+		var buf bytes.Buffer
+		logger := log.New(log.TerminalHandler(&buf, append(opts, log.WithNestedFormat(log.NestedFlatten))...))
+		logger.Info("hello", empty...)
+		assertSubstring(t, buf.String(), " m={} s={} l=[] n.inner={}\n")
+		buf.Reset()
+		logger = log.New(log.LogfmtHandler(&buf, append(opts, log.WithNestedFormat(log.NestedFlatten))...))
+		logger.Info("hello", empty...)
+		assertEqual(t, buf.String(), "lvl=info msg=hello m={} s={} l=[] n.inner={}\n")
+	})
+	t.Run("logfmt json", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := log.New(log.LogfmtHandler(&buf, append(opts, log.WithNestedFormat(log.NestedJSON))...))
+		logger.Info("hello", "v", value)
+		assertEqual(t, buf.String(), `lvl=info msg=hello v="{\"list\":[1,2,3,\"...+1\"],\"s\":{\"name\":\"foo\",\"Deep\":{\"x\":\"...\"}}}"`+"\n")
+	})
+	t.Run("terminal json", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := log.New(log.TerminalHandler(&buf, append(opts, log.WithNestedFormat(log.NestedJSON))...))
+		logger.Info("hello", "v", value)
+		assertSubstring(t, buf.String(), ` v={"list":[1,2,3,"...+1"],"s":{"name":"foo","Deep":{"x":"..."}}}`+"\n")
+	})
+}
+
+func TestSourceFormat(t *testing.T) {
+	handlers := []struct {
+		name string
+		new  func(w io.Writer, opts ...log.FormatOption) slog.Handler
+		want string
+	}{
+		{"terminal", log.TerminalHandler, "log/format_test.go:%d log_test.TestSourceFormat "},
+		{"logfmt", log.LogfmtHandler, "source=log/format_test.go:%d function=log_test.TestSourceFormat "},
+		{"json", log.JSONHandler, `"source":{"function":"log_test.TestSourceFormat","file":"log/format_test.go","line":%d}`},
+	}
+	for _, h := range handlers {
+		var buf bytes.Buffer
+		logger := log.New(h.new(&buf, log.WithExcludeTime(true), log.WithIncludeSource(true),
+			log.WithSourceModule(""), log.WithSourceFunc(true)))
+		_, _, line, _ := runtime.Caller(0)
+		logger.Info("hello")
+		assertSubstring(t, buf.String(), fmt.Sprintf(h.want, line+1))
+
+		// the source info of every handler can be read back
+		recs := readAll(t, log.NewReader(&buf))
+		assertEqual(t, len(recs), 1)
+		src, ok := recordAttrs(recs[0])[slog.SourceKey].Any().(*slog.Source)
+		assertTrue(t, ok)
+		assertEqual(t, *src, slog.Source{Function: "log_test.TestSourceFormat", File: "log/format_test.go", Line: line + 1})
+	}
+}
@@ -4,47 +4,62 @@ import (
 	"fmt"
 	"log/slog"
 	"math/big"
-	"path/filepath"
 	"reflect"
+	"sync/atomic"
 	"time"
 )
 
-func (cfg *FormatConfig) BuiltinReplace(_ []string, attr slog.Attr, logfmt bool) slog.Attr {
-	switch attr.Key {
-	case slog.TimeKey:
-		if cfg.ExcludeTime {
-			return slog.Attr{}
-		}
-		if attr.Value.Kind() == slog.KindTime {
-			if logfmt {
-				return slog.String("t", attr.Value.Time().Format(timeFormat))
-			} else {
-				return slog.Attr{Key: "t", Value: attr.Value}
+func (cfg *FormatConfig) BuiltinReplace(groups []string, attr slog.Attr, logfmt bool) slog.Attr {
+	if len(groups) == 0 {
+		switch attr.Key {
+		case slog.TimeKey:
+			if cfg.ExcludeTime {
+				return slog.Attr{}
+			}
+			if attr.Value.Kind() == slog.KindTime {
+				if logfmt {
+					return slog.String("t", cfg.recordTime.format(attr.Value.Time()))
+				} else {
+					return slog.Attr{Key: "t", Value: attr.Value}
+				}
+			}
+		case slog.LevelKey:
+			if l, ok := attr.Value.Any().(slog.Level); ok {
+				attr = slog.String("lvl", LevelString(l))
+				return attr
+			}
+		case slog.SourceKey:
+			if !cfg.IncludeSource {
+				return slog.Attr{}
+			}
+			// the source of the record, not the already rendered source attributes of the inline group
+			if s, ok := attr.Value.Any().(*slog.Source); ok && attr.Value.Kind() == slog.KindAny {
+				return cfg.sourceAttr(s, logfmt)
 			}
 		}
-	case slog.LevelKey:
-		if l, ok := attr.Value.Any().(slog.Level); ok {
-			attr = slog.Any("lvl", LevelString(l))
+	}
+
+	if logfmt && cfg.NestedFormat != NestedDefault {
+		if v, ok := cfg.nestedValue(attr.Value); ok {
+			attr.Value = v
 			return attr
 		}
-	case slog.SourceKey:
-		if !cfg.IncludeSource {
-			return slog.Attr{}
-		} else if cfg.SourceRelDir != "" {
-			// Hacky, to access and mutate the inner Source data, for adjustment of the filepath
-			s := attr.Value.Resolve().Any().(*slog.Source)
-			file, err := filepath.Rel(cfg.SourceRelDir, s.File)
-			if err == nil {
-				s.File = file
-			}
-		}
 	}
 
-	switch v := attr.Value.Any().(type) {
-	case time.Time:
+	switch attr.Value.Kind() {
+	case slog.KindTime:
 		if logfmt {
-			attr = slog.String(attr.Key, v.Format(timeFormat))
+			attr = slog.String(attr.Key, attr.Value.Time().Format(timeFormat))
 		}
+		return attr
+	case slog.KindAny:
+	default:
+		// Only values of kind Any may need replacement,
+		// other kinds are not converted with Any(), to avoid allocations.
+		return attr
+	}
+
+	switch v := attr.Value.Any().(type) {
 	case *big.Int:
 		if v == nil {
 			attr.Value = slog.StringValue("<nil>")
@@ -66,3 +81,30 @@ func (cfg *FormatConfig) BuiltinReplace(_ []string, attr slog.Attr, logfmt bool)
 	}
 	return attr
 }
+
+// timeCache caches the last formatted record time.
+// The time format has a resolution of seconds, so records logged within the same second share the string,
+// instead of allocating a new one for every record.
+type timeCache struct {
+	last atomic.Pointer[cachedTime]
+}
+
+type cachedTime struct {
+	unix int64
+	loc  *time.Location
+	text string
+}
+
+// format formats the time with timeFormat. A nil cache formats without caching.
+func (c *timeCache) format(t time.Time) string {
+	if c == nil {
+		return t.Format(timeFormat)
+	}
+	unix, loc := t.Unix(), t.Location()
+	if last := c.last.Load(); last != nil && last.unix == unix && last.loc == loc {
+		return last.text
+	}
+	text := t.Format(timeFormat)
+	c.last.Store(&cachedTime{unix: unix, loc: loc, text: text})
+	return text
+}
@@ -0,0 +1,68 @@
+package log
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// HandlerScope forms a chain of the attributes and groups that a handler was derived with,
+// through WithAttrs and WithGroup. Each scope either opens a group, or adds attributes.
+// A nil *HandlerScope is the empty root scope.
+type HandlerScope struct {
+	Parent *HandlerScope
+	// Group is the name of the group that this scope opened, if any.
+	Group string
+	// Attributes are the attributes that this scope added, qualified by the groups of the scope.
+	Attributes []slog.Attr
+}
+
+func (s *HandlerScope) withAttrs(attrs []slog.Attr) *HandlerScope {
+	return &HandlerScope{Parent: s, Attributes: attrs}
+}
+
+func (s *HandlerScope) withGroup(name string) *HandlerScope {
+	return &HandlerScope{Parent: s, Group: name}
+}
+
+// Groups returns the names of the open groups, outermost first.
+// Attributes that are added to a log record are qualified by these groups.
+func (s *HandlerScope) Groups() []string {
+	var out []string
+	for ; s != nil; s = s.Parent {
+		if s.Group != "" {
+			out = append(out, s.Group)
+		}
+	}
+	slices.Reverse(out)
+	return out
+}
+
+// Attrs calls f on each inherited attribute, most recently added first,
+// together with the groups that qualify the attribute, outermost first.
+// Iteration stops if f returns false.
+func (s *HandlerScope) Attrs(f func(groups []string, a slog.Attr) bool) {
+	groups := s.Groups()
+	for ; s != nil; s = s.Parent {
+		if s.Group != "" {
+			groups = groups[:len(groups)-1]
+			continue
+		}
+		for _, a := range s.Attributes {
+			if !f(groups, a) {
+				return
+			}
+		}
+	}
+}
+
+// apply derives the handler with the attributes and groups of the scope, in order.
+func (s *HandlerScope) apply(h slog.Handler) slog.Handler {
+	if s == nil {
+		return h
+	}
+	h = s.Parent.apply(h)
+	if s.Group != "" {
+		return h.WithGroup(s.Group)
+	}
+	return h.WithAttrs(s.Attributes)
+}
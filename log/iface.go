@@ -69,14 +69,50 @@ type ExtendedSLogLogger interface {
 	CritContext(ctx context.Context, msg string, args ...any)
 }
 
+// FormatLogger adds printf-style logging, that stays structured:
+// the format is the message, as template to group records by,
+// and the arguments are attributes "arg0", "arg1", etc.
+// The arguments are only converted to attributes if the level is enabled.
+type FormatLogger interface {
+	// Tracef logs at [LevelTrace], with the format as message and the arguments as attributes.
+	Tracef(format string, args ...any)
+
+	// Debugf logs at [LevelDebug], with the format as message and the arguments as attributes.
+	Debugf(format string, args ...any)
+
+	// Infof logs at [LevelInfo], with the format as message and the arguments as attributes.
+	Infof(format string, args ...any)
+
+	// Warnf logs at [LevelWarn], with the format as message and the arguments as attributes.
+	Warnf(format string, args ...any)
+
+	// Errorf logs at [LevelError], with the format as message and the arguments as attributes.
+	Errorf(format string, args ...any)
+
+	// Critf logs at [LevelCrit], with the format as message and the arguments as attributes.
+	Critf(format string, args ...any)
+}
+
+// Logger is the structured logger of this package, created with New.
+//
+// Log calls check the level before any record is built, so a disabled call does not format,
+// convert or allocate anything by itself. The variadic arguments are built by the caller though:
+// if the concrete type of the Logger is unknown to the compiler, e.g. a Logger in a struct field,
+// the arguments escape, and a disabled call still allocates its argument slice.
+// Guard hot disabled log calls with Enabled to avoid that.
 type Logger interface {
 	ExtendedSLogLogger
+	FormatLogger
 
 	// With returns a Logger that includes the given attributes
 	// in each output operation. Arguments are converted to
 	// attributes as if by [Logger.Log].
 	With(args ...any) Logger
 
+	// WithCallerSkip returns a Logger that skips skip additional frames to find the source of a log call.
+	// This is for logging helper functions, to attribute records to the caller of the helper, see also Helper.
+	WithCallerSkip(skip int) Logger
+
 	// WithGroup returns a Logger that starts a group, if name is non-empty.
 	// The keys of all attributes added to the Logger will be qualified by the given
 	// name. (How that qualification happens depends on the [Handler.WithGroup]
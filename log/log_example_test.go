@@ -71,3 +71,22 @@ func ExampleLevelHandler_SetMinLevel() {
 	// INFO  Report from sub-logger                   name=alice
 	// DEBUG Hello debug world from sub-logger        name=alice
 }
+
+func ExampleWithNestedFormat() {
+	h := log.LogfmtHandler(os.Stdout,
+		log.WithExcludeTime(true),
+		log.WithNestedFormat(log.NestedFlatten),
+	)
+	logger := log.New(h)
+	logger.Info("Hello Logfmt", "example", map[string]any{"hello": 123, "list": []int{1, 2}})
+
+	h = log.TerminalHandler(os.Stdout,
+		log.WithExcludeTime(true),
+		log.WithNestedFormat(log.NestedJSON),
+	)
+	logger = log.New(h)
+	logger.Info("Hello JSON", "example", map[string]any{"hello": 123, "list": []int{1, 2}})
+	// Output:
+	// lvl=info msg="Hello Logfmt" example.hello=123 example.list.0=1 example.list.1=2
+	// INFO  Hello JSON                               example={"hello":123,"list":[1,2]}
+}
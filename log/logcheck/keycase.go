@@ -0,0 +1,147 @@
+package logcheck
+
+import (
+	"fmt"
+	"go/constant"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeyCase is a naming convention of attribute keys.
+// Dotted keys, that select attributes within groups, are converted per segment.
+type KeyCase uint8
+
+const (
+	// KeyCaseAny allows any key, as long as keys are spelled consistently.
+	KeyCaseAny KeyCase = iota
+	// KeyCaseSnake is like "block_num".
+	KeyCaseSnake
+	// KeyCaseCamel is like "blockNum".
+	KeyCaseCamel
+	// KeyCaseKebab is like "block-num".
+	KeyCaseKebab
+	// KeyCaseLower is like "blocknum".
+	KeyCaseLower
+)
+
+func (k KeyCase) String() string {
+	switch k {
+	case KeyCaseAny:
+		return "any"
+	case KeyCaseSnake:
+		return "snake"
+	case KeyCaseCamel:
+		return "camel"
+	case KeyCaseKebab:
+		return "kebab"
+	case KeyCaseLower:
+		return "lower"
+	default:
+		return fmt.Sprintf("KeyCase(%d)", uint8(k))
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k KeyCase) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, for use as flag.
+func (k *KeyCase) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "", "any":
+		*k = KeyCaseAny
+	case "snake":
+		*k = KeyCaseSnake
+	case "camel":
+		*k = KeyCaseCamel
+	case "kebab":
+		*k = KeyCaseKebab
+	case "lower":
+		*k = KeyCaseLower
+	default:
+		return fmt.Errorf("unknown key case %q, expected any, snake, camel, kebab or lower", text)
+	}
+	return nil
+}
+
+// Convert converts the key to the naming convention.
+// KeyCaseAny returns the key as-is.
+func (k KeyCase) Convert(key string) string {
+	if k == KeyCaseAny {
+		return key
+	}
+	segments := strings.Split(key, ".")
+	for i, seg := range segments {
+		segments[i] = k.convertSegment(seg)
+	}
+	return strings.Join(segments, ".")
+}
+
+func (k KeyCase) convertSegment(seg string) string {
+	words := splitWords(seg)
+	if len(words) == 0 {
+		return seg
+	}
+	switch k {
+	case KeyCaseSnake:
+		return strings.Join(words, "_")
+	case KeyCaseKebab:
+		return strings.Join(words, "-")
+	case KeyCaseLower:
+		return strings.Join(words, "")
+	case KeyCaseCamel:
+		var sb strings.Builder
+		sb.WriteString(words[0])
+		for _, w := range words[1:] {
+			r, size := utf8.DecodeRuneInString(w)
+			sb.WriteRune(unicode.ToUpper(r))
+			sb.WriteString(w[size:])
+		}
+		return sb.String()
+	default:
+		return seg
+	}
+}
+
+// splitWords splits a key into lowercase words, at separators and camel-case humps.
+// Acronyms stay together, e.g. "peerID" and "HTTPServer" are split into "peer", "id" and "http", "server".
+func splitWords(s string) (words []string) {
+	runes := []rune(s)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, strings.ToLower(string(runes[start:end])))
+		}
+		start = end
+	}
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush(i)
+			start = i + 1
+		case unicode.IsUpper(r) && i > start:
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush(i)
+			}
+		}
+	}
+	flush(len(runes))
+	return words
+}
+
+// normalizeKey returns the key without casing and separators, to compare spellings.
+func normalizeKey(key string) string {
+	return strings.Join(splitWords(key), "")
+}
+
+func constantString(tv types.TypeAndValue) string {
+	if tv.Value.Kind() != constant.String {
+		return tv.Value.ExactString()
+	}
+	return constant.StringVal(tv.Value)
+}
@@ -0,0 +1,384 @@
+// Package logcheck finds mistakes in logging calls, which would otherwise only show up at runtime,
+// like mismatched key/value arguments that turn into "!BADKEY" attributes.
+//
+// The checks work on type-checked syntax trees, with the standard library only,
+// so they can be embedded in analysis drivers, like go vet tools.
+package logcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+)
+
+// LoggerPackages are the import paths of the packages of which the logging functions and methods are checked.
+// This covers the Logger, ExtendedSLogLogger and SLogLogger interfaces, and *slog.Logger.
+var LoggerPackages = []string{
+	"github.com/protolambda/proto-log/log",
+	"log/slog",
+}
+
+// Categories of diagnostics.
+const (
+	CategoryArity       = "arity"
+	CategoryKey         = "key"
+	CategoryKeyCase     = "keycase"
+	CategoryContext     = "context"
+	CategoryNonConstant = "nonconst"
+)
+
+// Diagnostic is a problem found in a logging call.
+type Diagnostic struct {
+	Pos      token.Pos
+	End      token.Pos
+	Category string
+	Message  string
+	Fixes    []Fix
+}
+
+// Fix is a suggested fix of a diagnostic.
+type Fix struct {
+	Message string
+	Edits   []TextEdit
+}
+
+// TextEdit replaces the source text in the range [Pos, End) with NewText.
+type TextEdit struct {
+	Pos     token.Pos
+	End     token.Pos
+	NewText []byte
+}
+
+// Config configures the checks.
+type Config struct {
+	// KeyCase is the naming convention that attribute keys must follow.
+	// With KeyCaseAny, keys are only checked to be spelled consistently within the checked files.
+	KeyCase KeyCase
+	// NoContextCheck disables the check for calls like Info, where InfoContext could pass a context.Context in scope.
+	NoContextCheck bool
+}
+
+// CheckFiles checks the key/value arguments of all logging calls in the files.
+// See Config.Check for the other checks.
+func CheckFiles(info *types.Info, files []*ast.File) (out []Diagnostic) {
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				out = append(out, CheckCall(info, call)...)
+			}
+			return true
+		})
+	}
+	return out
+}
+
+// CheckCall checks the key/value arguments of the call, if it is a logging call.
+// Each argument is expected to either be a slog.Attr, or a string key followed by a value.
+func CheckCall(info *types.Info, call *ast.CallExpr) []Diagnostic {
+	c := &checker{info: info}
+	c.checkArgs(call)
+	return c.out
+}
+
+// Check runs all checks on the logging calls in the files of the type-checked package:
+// the key/value arguments, like CheckFiles, and the keys and context usage, as configured.
+func (cfg *Config) Check(pkg *types.Package, info *types.Info, files []*ast.File) []Diagnostic {
+	c := &checker{cfg: cfg, pkg: pkg, info: info, spellings: make(map[string]string)}
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if call, ok := n.(*ast.CallExpr); ok {
+				if !cfg.NoContextCheck {
+					c.checkContext(call)
+				}
+				c.checkArgs(call)
+			}
+			return true
+		})
+	}
+	return c.out
+}
+
+type checker struct {
+	cfg  *Config // nil to only check the key/value arguments
+	pkg  *types.Package
+	info *types.Info
+	// spellings maps normalized keys to the first seen spelling, to detect inconsistent key casing
+	spellings map[string]string
+	out       []Diagnostic
+}
+
+func (c *checker) report(d Diagnostic) {
+	c.out = append(c.out, d)
+}
+
+// KeyValueFuncs are the names of the logging functions and methods of the LoggerPackages
+// that take key/value arguments, mapped to the number of parameters before the variadic ...any parameter.
+// Functions of other packages that follow the same convention can be added to LoggerPackages and KeyValueFuncs.
+var KeyValueFuncs = map[string]int{
+	"With":  0,
+	"Group": 1,
+	"Log":   3,
+
+	"Trace": 1, "TraceContext": 2,
+	"Debug": 1, "DebugContext": 2,
+	"Info": 1, "InfoContext": 2,
+	"Warn": 1, "WarnContext": 2,
+	"Error": 1, "ErrorContext": 2,
+	"Crit": 1, "CritContext": 2,
+}
+
+// KeyValueArgs returns the key/value arguments of the call, or ok=false if the call is not a logging call.
+//
+// A logging call is a call of one of the KeyValueFuncs of the LoggerPackages,
+// with the expected number of parameters, followed by a final variadic ...any parameter.
+// Other functions with the same name, like Error(args ...any) of a testing.TB, are not logging calls,
+// and neither are printf-style calls like Infof.
+// Calls that pass a slice with "args..." are not considered, since the individual arguments are unknown.
+func KeyValueArgs(info *types.Info, call *ast.CallExpr) (args []ast.Expr, ok bool) {
+	if call.Ellipsis.IsValid() {
+		return nil, false
+	}
+	fn := loggerFunc(info, call)
+	if fn == nil {
+		return nil, false
+	}
+	sig := fn.Type().(*types.Signature)
+	params := sig.Params()
+	if !sig.Variadic() || params.Len() == 0 {
+		return nil, false
+	}
+	last := params.At(params.Len() - 1).Type().(*types.Slice)
+	if iface, ok := last.Elem().Underlying().(*types.Interface); !ok || !iface.Empty() {
+		return nil, false
+	}
+	fixed, ok := KeyValueFuncs[fn.Name()]
+	if !ok || params.Len() != fixed+1 {
+		return nil, false
+	}
+	if len(call.Args) < fixed {
+		return nil, false
+	}
+	return call.Args[fixed:], true
+}
+
+// loggerFunc returns the called function or method, if it is declared in one of the LoggerPackages.
+func loggerFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	fn, ok := callee(info, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || !slices.Contains(LoggerPackages, fn.Pkg().Path()) {
+		return nil
+	}
+	return fn
+}
+
+// checkArgs checks the key/value arguments of a logging call, and the keys if there is a config.
+func (c *checker) checkArgs(call *ast.CallExpr) {
+	args, ok := KeyValueArgs(c.info, call)
+	if !ok {
+		return
+	}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		t := c.info.TypeOf(arg)
+		switch {
+		case t == nil:
+			return // not type-checked
+		case IsAttr(t):
+			continue
+		case isString(t):
+			if i == len(args)-1 {
+				// remove the key, including the preceding comma, if any
+				start := arg.Pos()
+				if j := len(call.Args) - len(args) + i; j > 0 {
+					start = call.Args[j-1].End()
+				}
+				c.report(Diagnostic{Pos: arg.Pos(), End: arg.End(), Category: CategoryArity,
+					Message: fmt.Sprintf("key %s is missing a value", types.ExprString(arg)),
+					Fixes: []Fix{{
+						Message: "Remove the key",
+						Edits:   []TextEdit{{Pos: start, End: arg.End()}},
+					}},
+				})
+				return
+			}
+			if c.cfg != nil {
+				c.checkKey(arg)
+			}
+			i++ // skip the value
+		default:
+			d := Diagnostic{Pos: arg.Pos(), End: arg.End(), Category: CategoryKey,
+				Message: fmt.Sprintf("expected a string key or slog.Attr, got %s of type %s", types.ExprString(arg), t)}
+			if name := exprName(arg); name != "" {
+				key := name
+				if c.cfg != nil {
+					key = c.cfg.KeyCase.Convert(name)
+				}
+				d.Fixes = []Fix{{
+					Message: fmt.Sprintf("Add key %q", key),
+					Edits:   []TextEdit{{Pos: arg.Pos(), End: arg.Pos(), NewText: []byte(strconv.Quote(key) + ", ")}},
+				}}
+			}
+			c.report(d)
+		}
+	}
+}
+
+// checkKey checks that the key is constant, and follows the naming convention.
+func (c *checker) checkKey(arg ast.Expr) {
+	tv := c.info.Types[arg]
+	if tv.Value == nil {
+		c.report(Diagnostic{Pos: arg.Pos(), End: arg.End(), Category: CategoryNonConstant,
+			Message: fmt.Sprintf("key %s is not a constant", types.ExprString(arg))})
+		return
+	}
+	key := constantString(tv)
+	var want string
+	if c.cfg.KeyCase != KeyCaseAny {
+		if want = c.cfg.KeyCase.Convert(key); want == key {
+			return
+		}
+	} else {
+		norm := normalizeKey(key)
+		first, seen := c.spellings[norm]
+		if !seen {
+			c.spellings[norm] = key
+			return
+		}
+		if first == key {
+			return
+		}
+		want = first
+	}
+	d := Diagnostic{Pos: arg.Pos(), End: arg.End(), Category: CategoryKeyCase}
+	if c.cfg.KeyCase != KeyCaseAny {
+		d.Message = fmt.Sprintf("key %q does not follow the %s naming convention, expected %q", key, c.cfg.KeyCase, want)
+	} else {
+		d.Message = fmt.Sprintf("key %q is inconsistent with key %q", key, want)
+	}
+	if _, isLit := ast.Unparen(arg).(*ast.BasicLit); isLit {
+		d.Fixes = []Fix{{
+			Message: fmt.Sprintf("Rename key to %q", want),
+			Edits:   []TextEdit{{Pos: arg.Pos(), End: arg.End(), NewText: []byte(strconv.Quote(want))}},
+		}}
+	}
+	c.report(d)
+}
+
+// contextLevels are the logging methods and functions that have a Context variant.
+var contextLevels = []string{"Trace", "Debug", "Info", "Warn", "Error", "Crit"}
+
+// checkContext reports calls like Info, if a context.Context is in scope, and InfoContext exists.
+func (c *checker) checkContext(call *ast.CallExpr) {
+	fn := loggerFunc(c.info, call)
+	if fn == nil || !slices.Contains(contextLevels, fn.Name()) || len(call.Args) == 0 || c.pkg == nil {
+		return
+	}
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	ctxName := fn.Name() + "Context"
+	if selection, ok := c.info.Selections[sel]; ok {
+		obj, _, _ := types.LookupFieldOrMethod(selection.Recv(), true, c.pkg, ctxName)
+		if _, ok := obj.(*types.Func); !ok {
+			return
+		}
+	} else if _, ok := fn.Pkg().Scope().Lookup(ctxName).(*types.Func); !ok {
+		return
+	}
+	ctx := contextInScope(c.pkg, call.Pos())
+	if ctx == nil {
+		return
+	}
+	c.report(Diagnostic{Pos: call.Pos(), End: call.End(), Category: CategoryContext,
+		Message: fmt.Sprintf("%s is called with %s in scope, use %s instead", fn.Name(), ctx.Name(), ctxName),
+		Fixes: []Fix{{
+			Message: fmt.Sprintf("Use %s(%s, ...)", ctxName, ctx.Name()),
+			Edits: []TextEdit{
+				{Pos: sel.Sel.Pos(), End: sel.Sel.End(), NewText: []byte(ctxName)},
+				{Pos: call.Args[0].Pos(), End: call.Args[0].Pos(), NewText: []byte(ctx.Name() + ", ")},
+			},
+		}},
+	})
+}
+
+// contextInScope returns the innermost context.Context variable that is declared before pos,
+// within the function that contains pos, or nil if there is none.
+func contextInScope(pkg *types.Package, pos token.Pos) *types.Var {
+	scope := pkg.Scope().Innermost(pos)
+	for ; scope != nil && scope != pkg.Scope(); scope = scope.Parent() {
+		var found *types.Var
+		for _, name := range scope.Names() {
+			v, ok := scope.Lookup(name).(*types.Var)
+			if !ok || name == "_" || v.Pos() > pos || !isContext(v.Type()) {
+				continue
+			}
+			if found == nil || v.Pos() > found.Pos() {
+				found = v
+			}
+		}
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func isContext(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+// IsAttr checks if t is the slog.Attr type.
+func IsAttr(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "log/slog" && obj.Name() == "Attr"
+}
+
+func isString(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// exprName returns the name of an identifier or field selection, to derive a key from.
+func exprName(e ast.Expr) string {
+	switch e := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
+
+// callee returns the function or method that is called, or nil if it is not statically known.
+// This is like golang.org/x/tools/go/types/typeutil.Callee, without the dependency.
+func callee(info *types.Info, call *ast.CallExpr) types.Object {
+	fun := ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr: // generic function instantiation
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+	switch f := fun.(type) {
+	case *ast.Ident:
+		return info.Uses[f]
+	case *ast.SelectorExpr:
+		if sel, ok := info.Selections[f]; ok {
+			return sel.Obj()
+		}
+		return info.Uses[f.Sel] // qualified identifier
+	}
+	return nil
+}
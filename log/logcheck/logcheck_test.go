@@ -0,0 +1,165 @@
+package logcheck
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+)
+
+// stubLogPkg declares the parts of the log package that are relevant to the checks.
+const stubLogPkg = `package log
+
+import "context"
+
+type Logger interface {
+	Info(msg string, args ...any)
+	Infof(format string, args ...any)
+	InfoContext(ctx context.Context, msg string, args ...any)
+	With(args ...any) Logger
+}
+
+// T has a variadic Error method that does not take key/value arguments.
+type T interface {
+	Error(args ...any)
+}
+`
+
+const testSrc = `package example
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func example(logger log.Logger, key string, args []any) {
+	logger.Info("ok", "a", 1, slog.Int("b", 2), key, 3)
+	logger.Info("odd", "a", 1, "b")
+	logger.Info("no key", 123, "c")
+	logger.With("peer", "abc", "orphan")
+	logger.Infof("format %d", 123)
+	logger.Info("spread", args...)
+	slog.Info("slog", "a")
+	slog.Group("g", 1, 2)
+	logger.Info("casing", "blockNum", 1, "block_num", 2, "peer.id", 3)
+}
+
+func notLogging(t log.T) {
+	t.Error("failed", 1, "b")
+}
+
+func withContext(ctx context.Context, logger log.Logger, sl *slog.Logger) {
+	logger.Info("hello")
+	sl.Debug("hello")
+	logger.Infof("no context variant")
+}
+`
+
+// stubImporter serves the stub log package, and imports other packages from source.
+type stubImporter struct {
+	fset *token.FileSet
+	std  types.Importer
+}
+
+func (s *stubImporter) Import(path string) (*types.Package, error) {
+	if path != LoggerPackages[0] {
+		return s.std.Import(path)
+	}
+	f, err := parser.ParseFile(s.fset, "log.go", stubLogPkg, 0)
+	if err != nil {
+		return nil, err
+	}
+	conf := types.Config{Importer: s.std}
+	return conf.Check(path, s.fset, []*ast.File{f}, nil)
+}
+
+// typeCheck parses and type-checks testSrc.
+func typeCheck(t *testing.T) (*token.FileSet, *types.Package, *types.Info, *ast.File) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "example.go", testSrc, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
+	}
+	conf := types.Config{Importer: &stubImporter{fset: fset, std: importer.ForCompiler(fset, "source", nil)}}
+	pkg, err := conf.Check("example", fset, []*ast.File{f}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fset, pkg, info, f
+}
+
+func checkDiagnostics(t *testing.T, fset *token.FileSet, diagnostics []Diagnostic, want []string) {
+	t.Helper()
+	var got []string
+	for _, d := range diagnostics {
+		got = append(got, fset.Position(d.Pos).String()+": "+d.Message)
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected diagnostics:\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	fset, _, info, f := typeCheck(t)
+	checkDiagnostics(t, fset, CheckFiles(info, []*ast.File{f}), []string{
+		`example.go:12:29: key "b" is missing a value`,
+		`example.go:13:24: expected a string key or slog.Attr, got 123 of type int`,
+		`example.go:13:29: key "c" is missing a value`,
+		`example.go:14:29: key "orphan" is missing a value`,
+		`example.go:17:20: key "a" is missing a value`,
+		`example.go:18:18: expected a string key or slog.Attr, got 1 of type int`,
+		`example.go:18:21: expected a string key or slog.Attr, got 2 of type int`,
+	})
+}
+
+func TestConfigCheck(t *testing.T) {
+	fset, pkg, info, f := typeCheck(t)
+	var cfg Config
+	checkDiagnostics(t, fset, cfg.Check(pkg, info, []*ast.File{f}), []string{
+		`example.go:11:46: key key is not a constant`,
+		`example.go:12:29: key "b" is missing a value`,
+		`example.go:13:24: expected a string key or slog.Attr, got 123 of type int`,
+		`example.go:13:29: key "c" is missing a value`,
+		`example.go:14:29: key "orphan" is missing a value`,
+		`example.go:17:20: key "a" is missing a value`,
+		`example.go:18:18: expected a string key or slog.Attr, got 1 of type int`,
+		`example.go:18:21: expected a string key or slog.Attr, got 2 of type int`,
+		`example.go:19:39: key "block_num" is inconsistent with key "blockNum"`,
+		`example.go:27:2: Info is called with ctx in scope, use InfoContext instead`,
+		`example.go:28:2: Debug is called with ctx in scope, use DebugContext instead`,
+	})
+}
+
+func TestKeyCase(t *testing.T) {
+	for _, tc := range []struct {
+		key  string
+		kc   KeyCase
+		want string
+	}{
+		{"blockNum", KeyCaseSnake, "block_num"},
+		{"block_num", KeyCaseCamel, "blockNum"},
+		{"peer_ältester", KeyCaseCamel, "peerÄltester"},
+		{"größe_über", KeyCaseCamel, "größeÜber"},
+		{"peerID", KeyCaseSnake, "peer_id"},
+		{"HTTPServer", KeyCaseKebab, "http-server"},
+		{"peer.remoteAddr", KeyCaseSnake, "peer.remote_addr"},
+		{"Block-Num", KeyCaseLower, "blocknum"},
+		{"blockNum", KeyCaseAny, "blockNum"},
+	} {
+		if got := tc.kc.Convert(tc.key); got != tc.want {
+			t.Errorf("%s in %s case: got %q, want %q", tc.key, tc.kc, got, tc.want)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 func LogfmtHandler(wr io.Writer, opts ...FormatOption) slog.Handler {
 	var cfg FormatConfig
 	cfg.Apply(opts...)
+	cfg.recordTime = new(timeCache)
 	hOpts := &slog.HandlerOptions{
 		AddSource: cfg.IncludeSource,
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
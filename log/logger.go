@@ -7,10 +7,8 @@ package log
 import (
 	"context"
 	"log/slog"
-	"runtime"
+	"strconv"
 	"time"
-
-	"github.com/protolambda/proto-log/log/internal/logsettings"
 )
 
 // A loggerImpl records structured information about each call to its
@@ -21,10 +19,20 @@ import (
 // that begins "With".
 type loggerImpl struct {
 	handler slog.Handler // for structured logging
+	skipPC  bool         // if the handler stack has a SkipPCHandler
+	// callerSkip is the number of frames to skip, between the log call and the source of the record
+	callerSkip int
 }
 
 // New creates a new Logger with the given non-nil Handler.
 func New(h slog.Handler, mods ...HandlerMod) Logger {
+	// Small enough to be inlined, so the compiler may see the concrete type of the Logger,
+	// and call its methods directly: variadic arguments then do not escape to the heap.
+	// This is an optimization only, see the Logger docs for what disabled calls cost otherwise.
+	return newLogger(h, mods)
+}
+
+func newLogger(h slog.Handler, mods []HandlerMod) *loggerImpl {
 	if h == nil {
 		panic("nil Handler")
 	}
@@ -35,7 +43,8 @@ func New(h slog.Handler, mods ...HandlerMod) Logger {
 		// if there is no ContextHandler in the stack, add it
 		h = ContextMod()(h)
 	}
-	return &loggerImpl{handler: h}
+	_, skipPC := FindHandler[*SkipPCHandler](h)
+	return &loggerImpl{handler: h, skipPC: skipPC}
 }
 
 func (l *loggerImpl) clone() *loggerImpl {
@@ -50,6 +59,11 @@ func (l *loggerImpl) Handler() slog.Handler { return l.handler }
 // in each output operation. Arguments are converted to
 // attributes as if by [Logger.Log].
 func (l *loggerImpl) With(args ...any) Logger {
+	// inlined, like New, so the compiler can see the concrete type of the derived Logger
+	return l.with(args)
+}
+
+func (l *loggerImpl) with(args []any) *loggerImpl {
 	if len(args) == 0 {
 		return l
 	}
@@ -94,6 +108,14 @@ func argsToAttr(args []any) (slog.Attr, []any) {
 	}
 }
 
+// WithCallerSkip returns a Logger that skips skip additional frames to find the source of a log call.
+// This is for logging helper functions, to attribute records to the caller of the helper, see also Helper.
+func (l *loggerImpl) WithCallerSkip(skip int) Logger {
+	c := l.clone()
+	c.callerSkip += skip
+	return c
+}
+
 // WithGroup returns a Logger that starts a group, if name is non-empty.
 // The keys of all attributes added to the Logger will be qualified by the given
 // name. (How that qualification happens depends on the [Handler.WithGroup]
@@ -196,6 +218,36 @@ func (l *loggerImpl) CritContext(ctx context.Context, msg string, args ...any) {
 	l.log(ctx, LevelCrit, msg, args...)
 }
 
+// Tracef logs at [LevelTrace], with the format as message and the arguments as attributes.
+func (l *loggerImpl) Tracef(format string, args ...any) {
+	l.logf(LevelTrace, format, args)
+}
+
+// Debugf logs at [LevelDebug], with the format as message and the arguments as attributes.
+func (l *loggerImpl) Debugf(format string, args ...any) {
+	l.logf(LevelDebug, format, args)
+}
+
+// Infof logs at [LevelInfo], with the format as message and the arguments as attributes.
+func (l *loggerImpl) Infof(format string, args ...any) {
+	l.logf(LevelInfo, format, args)
+}
+
+// Warnf logs at [LevelWarn], with the format as message and the arguments as attributes.
+func (l *loggerImpl) Warnf(format string, args ...any) {
+	l.logf(LevelWarn, format, args)
+}
+
+// Errorf logs at [LevelError], with the format as message and the arguments as attributes.
+func (l *loggerImpl) Errorf(format string, args ...any) {
+	l.logf(LevelError, format, args)
+}
+
+// Critf logs at [LevelCrit], with the format as message and the arguments as attributes.
+func (l *loggerImpl) Critf(format string, args ...any) {
+	l.logf(LevelCrit, format, args)
+}
+
 // log is the low-level logging method for methods that take ...any.
 // It must always be called directly by an exported logging method
 // or function, because it uses a fixed call depth to obtain the pc.
@@ -204,11 +256,9 @@ func (l *loggerImpl) log(ctx context.Context, level slog.Level, msg string, args
 		return
 	}
 	var pc uintptr
-	if !logsettings.IgnorePC {
-		var pcs [1]uintptr
-		// skip [runtime.Callers, this function, this function's caller]
-		runtime.Callers(3, pcs[:])
-		pc = pcs[0]
+	if !l.skipPC {
+		// skip [this function, this function's caller]
+		pc = CallerPC(2 + l.callerSkip)
 	}
 	r := slog.NewRecord(time.Now(), level, msg, pc)
 	r.Add(args...)
@@ -224,11 +274,9 @@ func (l *loggerImpl) logAttrs(ctx context.Context, level slog.Level, msg string,
 		return
 	}
 	var pc uintptr
-	if !logsettings.IgnorePC {
-		var pcs [1]uintptr
-		// skip [runtime.Callers, this function, this function's caller]
-		runtime.Callers(3, pcs[:])
-		pc = pcs[0]
+	if !l.skipPC {
+		// skip [this function, this function's caller]
+		pc = CallerPC(2 + l.callerSkip)
 	}
 	r := slog.NewRecord(time.Now(), level, msg, pc)
 	r.AddAttrs(attrs...)
@@ -238,6 +286,36 @@ func (l *loggerImpl) logAttrs(ctx context.Context, level slog.Level, msg string,
 	_ = l.Handler().Handle(ctx, r)
 }
 
+// logf is like [Logger.log], but for printf-style methods.
+// The format is the message, and the arguments are added as "arg0", "arg1", etc. attributes.
+func (l *loggerImpl) logf(level slog.Level, format string, args []any) {
+	ctx := context.Background()
+	if !l.Enabled(ctx, level) {
+		return
+	}
+	var pc uintptr
+	if !l.skipPC {
+		// skip [this function, this function's caller]
+		pc = CallerPC(2 + l.callerSkip)
+	}
+	r := slog.NewRecord(time.Now(), level, format, pc)
+	for i, arg := range args {
+		r.AddAttrs(slog.Any(FormatArgKey(i), arg))
+	}
+	_ = l.Handler().Handle(ctx, r)
+}
+
+// formatArgKeys are the common format argument keys, to not allocate them on every log call.
+var formatArgKeys = [...]string{"arg0", "arg1", "arg2", "arg3", "arg4", "arg5", "arg6", "arg7"}
+
+// FormatArgKey returns the attribute key of the i-th argument of a printf-style log call, like "arg0".
+func FormatArgKey(i int) string {
+	if i < len(formatArgKeys) {
+		return formatArgKeys[i]
+	}
+	return "arg" + strconv.Itoa(i)
+}
+
 // Context returns the default context that is used when logging
 func (l *loggerImpl) Context() context.Context {
 	h, ok := FindHandler[*ContextHandler](l.handler)
@@ -250,6 +328,12 @@ func (l *loggerImpl) Context() context.Context {
 // WithContext creates a clone, with the given context as new default context.
 func (l *loggerImpl) WithContext(ctx context.Context) Logger {
 	c := l.clone()
+	if h, ok := l.handler.(*ContextHandler); ok {
+		// The ContextHandler is the outermost handler, unless added by a custom HandlerMod.
+		// Then only the ContextHandler itself has to be copied.
+		c.handler = &ContextHandler{inner: h.inner, ctx: ctx}
+		return c
+	}
 	c.handler = l.handler.WithAttrs(nil)
 	h, ok := FindHandler[*ContextHandler](c.handler)
 	if !ok {
@@ -0,0 +1,91 @@
+package log_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func TestLoggerFormat(t *testing.T) {
+	logger := log.New(log.JSONHandler(io.Discard), log.CapturingMod())
+	c, _ := log.FindHandler[*log.CapturingHandler](logger.Handler())
+	logger.Warnf("%d of %s", 3, "peers")
+	rec := c.FindLog()
+	if rec == nil || rec.Level != log.LevelWarn || rec.Message != "%d of %s" {
+		t.Fatal("expected record with format as message")
+	}
+	if rec.AttrValue("arg0") != int64(3) || rec.AttrValue("arg1") != "peers" {
+		t.Fatal("expected arguments as attributes")
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{rec.PC}).Next()
+	if frame.Function != "github.com/protolambda/proto-log/log_test.TestLoggerFormat" {
+		t.Fatalf("unexpected source %s", frame.Function)
+	}
+	if log.FormatArgKey(12) != "arg12" {
+		t.Fatal("expected indexed key")
+	}
+}
+
+func TestLoggerFormatArgs(t *testing.T) {
+	logger := log.New(log.JSONHandler(io.Discard), log.CapturingMod())
+	c, _ := log.FindHandler[*log.CapturingHandler](logger.Handler())
+
+	// the arguments do not have to match the verbs, they are attributes either way
+	logger.Infof("%d and %d", 1)
+	rec := c.FindLog(log.MessageFilter("%d and %d"))
+	assertNotNil(t, rec)
+	assertEqual(t, rec.AttrValue("arg0"), any(int64(1)))
+	assertEqual(t, rec.AttrValue("arg1"), nil)
+
+	logger.Infof("no verbs", "a", 2)
+	rec = c.FindLog(log.MessageFilter("no verbs"))
+	assertNotNil(t, rec)
+	assertEqual(t, rec.AttrValue("arg0"), any("a"))
+	assertEqual(t, rec.AttrValue("arg1"), any(int64(2)))
+
+	// wrapped errors are kept as error values, not formatted
+	errNotFound := errors.New("not found")
+	logger.Errorf("lookup failed: %w", fmt.Errorf("peer: %w", errNotFound))
+	rec = c.FindLog(log.MessageFilter("lookup failed: %w"))
+	assertNotNil(t, rec)
+	err, ok := rec.AttrValue("arg0").(error)
+	assertTrue(t, ok)
+	assertTrue(t, errors.Is(err, errNotFound))
+	assertEqual(t, len(c.FindLogs(log.ErrIsFilter(errNotFound))), 1)
+}
+
+type service struct {
+	log log.Logger
+}
+
+//go:noinline
+func newService(lgr log.Logger) *service {
+	return &service{log: lgr}
+}
+
+func TestLoggerDisabledAllocs(t *testing.T) {
+	s := newService(log.New(log.JSONHandler(io.Discard), log.LevelMod(log.LevelInfo)).With("a", 1))
+	ctx := context.Background()
+
+	// Through an interface value, the argument slice escapes at the call site, but nothing else is allocated.
+	allocs := testing.AllocsPerRun(100, func() {
+		s.log.Debug("disabled", "int", 123, "str", "hello", "err", context.Canceled)
+	})
+	if allocs > 1 {
+		t.Fatalf("expected at most the argument slice to be allocated for disabled log calls, got %v allocations", allocs)
+	}
+
+	allocs = testing.AllocsPerRun(100, func() {
+		if s.log.Enabled(ctx, log.LevelDebug) {
+			s.log.Debug("disabled", "int", 123, "str", "hello", "err", context.Canceled)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations for guarded disabled log calls, got %v", allocs)
+	}
+}
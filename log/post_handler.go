@@ -5,15 +5,30 @@ import (
 	"log/slog"
 )
 
+// PostProcessFunc processes a log record after it was handled by the inner handler.
+// It receives the error of the inner handler, and returns the error to report to the caller.
+// The scope describes the attributes and groups that the handler was derived with.
+type PostProcessFunc func(ctx context.Context, scope *HandlerScope, r slog.Record, err error) error
+
 // PostProcessHandler allows you to post-process logs
 type PostProcessHandler struct {
 	inner slog.Handler
-	fn    HandlerFunc
+	fn    PostProcessFunc
+	scope *HandlerScope
 }
 
 var _ Handler = (*PostProcessHandler)(nil)
 
 func PostProcessMod(fn HandlerFunc) HandlerMod {
+	return PostProcessErrMod(func(ctx context.Context, scope *HandlerScope, r slog.Record, err error) error {
+		fn(ctx, r)
+		return err
+	})
+}
+
+// PostProcessErrMod is like PostProcessMod, but the function also receives the handler scope
+// and the error of the inner handler, and may replace the error.
+func PostProcessErrMod(fn PostProcessFunc) HandlerMod {
 	return func(h slog.Handler) slog.Handler {
 		return &PostProcessHandler{inner: h, fn: fn}
 	}
@@ -27,8 +42,10 @@ func (h *PostProcessHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
 	return h.inner.Enabled(ctx, lvl)
 }
 
-func (h *PostProcessHandler) Handle(ctx context.Context, r slog.Record) error {
-	defer h.fn(ctx, r)
+func (h *PostProcessHandler) Handle(ctx context.Context, r slog.Record) (err error) {
+	defer func() {
+		err = h.fn(ctx, h.scope, r, err)
+	}()
 	return h.inner.Handle(ctx, r)
 }
 
@@ -36,6 +53,7 @@ func (h *PostProcessHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &PostProcessHandler{
 		inner: h.inner.WithAttrs(attrs),
 		fn:    h.fn,
+		scope: h.scope.withAttrs(attrs),
 	}
 }
 
@@ -43,5 +61,11 @@ func (h *PostProcessHandler) WithGroup(name string) slog.Handler {
 	return &PostProcessHandler{
 		inner: h.inner.WithGroup(name),
 		fn:    h.fn,
+		scope: h.scope.withGroup(name),
 	}
 }
+
+// Scope returns the attributes and groups that the handler was derived with.
+func (h *PostProcessHandler) Scope() *HandlerScope {
+	return h.scope
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+type errHandler struct {
+	slog.Handler
+	err error
+}
+
+func (h *errHandler) Handle(ctx context.Context, r slog.Record) error {
+	_ = h.Handler.Handle(ctx, r)
+	return h.err
+}
+
+func (h *errHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &errHandler{Handler: h.Handler.WithAttrs(attrs), err: h.err}
+}
+
+func (h *errHandler) WithGroup(name string) slog.Handler {
+	return &errHandler{Handler: h.Handler.WithGroup(name), err: h.err}
+}
+
+func TestPreProcessMod(t *testing.T) {
+	var scopes [][]string
+	enrich := PreProcessMod(func(ctx context.Context, scope *HandlerScope, r slog.Record) (slog.Record, bool) {
+		if r.Message == "drop" {
+			return r, false
+		}
+		var keys []string
+		scope.Attrs(func(groups []string, a slog.Attr) bool {
+			keys = append(keys, strings.Join(append(groups, a.Key), "."))
+			return true
+		})
+		scopes = append(scopes, keys)
+		r = r.Clone()
+		r.AddAttrs(slog.Int("enriched", len(keys)))
+		return r, true
+	})
+	capt := CapturingMod()
+	logger := New(JSONHandler(io.Discard), capt, enrich)
+	c, ok := FindHandler[*CapturingHandler](logger.Handler())
+	if !ok {
+		t.Fatal("expected capturing handler")
+	}
+	logger.Info("drop")
+	logger.With("a", 1).WithGroup("g").With("b", 2).Info("keep")
+	if len(*c.Logs) != 1 {
+		t.Fatalf("expected 1 log, got %d", len(*c.Logs))
+	}
+	if got := (*c.Logs)[0].AttrValue("enriched"); got != int64(2) {
+		t.Fatalf("expected enriched record, got %v", got)
+	}
+	if got := strings.Join(scopes[0], ","); got != "g.b,a" {
+		t.Fatalf("unexpected scope attributes: %s", got)
+	}
+}
+
+func TestPostProcessErrMod(t *testing.T) {
+	innerErr := errors.New("write failed")
+	var seen []error
+	var groups []string
+	logger := New(&errHandler{Handler: JSONHandler(io.Discard), err: innerErr},
+		PostProcessErrMod(func(ctx context.Context, scope *HandlerScope, r slog.Record, err error) error {
+			seen = append(seen, err)
+			groups = scope.Groups()
+			return nil
+		}))
+	logger.WithGroup("peer").With("id", "abc").Info("hello")
+	if len(seen) != 1 || !errors.Is(seen[0], innerErr) {
+		t.Fatalf("expected inner error, got %v", seen)
+	}
+	if len(groups) != 1 || groups[0] != "peer" {
+		t.Fatalf("unexpected groups: %v", groups)
+	}
+}
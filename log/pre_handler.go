@@ -0,0 +1,65 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// PreProcessFunc processes a log record before it is handled by the inner handler.
+// It returns the record to handle, and keep=false to drop the record.
+// The scope describes the attributes and groups that the handler was derived with.
+// Note that copies of a record share attribute storage:
+// use slog.Record.Clone before adding attributes, if the record may be retained elsewhere.
+type PreProcessFunc func(ctx context.Context, scope *HandlerScope, r slog.Record) (out slog.Record, keep bool)
+
+// PreProcessHandler allows you to enrich, modify or drop logs, before they are handled.
+type PreProcessHandler struct {
+	inner slog.Handler
+	fn    PreProcessFunc
+	scope *HandlerScope
+}
+
+var _ Handler = (*PreProcessHandler)(nil)
+
+func PreProcessMod(fn PreProcessFunc) HandlerMod {
+	return func(h slog.Handler) slog.Handler {
+		return &PreProcessHandler{inner: h, fn: fn}
+	}
+}
+
+func (h *PreProcessHandler) Unwrap() slog.Handler {
+	return h.inner
+}
+
+func (h *PreProcessHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *PreProcessHandler) Handle(ctx context.Context, r slog.Record) error {
+	r, keep := h.fn(ctx, h.scope, r)
+	if !keep {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *PreProcessHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &PreProcessHandler{
+		inner: h.inner.WithAttrs(attrs),
+		fn:    h.fn,
+		scope: h.scope.withAttrs(attrs),
+	}
+}
+
+func (h *PreProcessHandler) WithGroup(name string) slog.Handler {
+	return &PreProcessHandler{
+		inner: h.inner.WithGroup(name),
+		fn:    h.fn,
+		scope: h.scope.withGroup(name),
+	}
+}
+
+// Scope returns the attributes and groups that the handler was derived with.
+func (h *PreProcessHandler) Scope() *HandlerScope {
+	return h.scope
+}
@@ -0,0 +1,743 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InputFormat is a log format that the Reader can parse.
+type InputFormat uint8
+
+const (
+	// InputAuto detects the format of each line.
+	InputAuto InputFormat = iota
+	// InputLogfmt parses the output of the LogfmtHandler.
+	InputLogfmt
+	// InputJSON parses the output of the JSONHandler.
+	InputJSON
+	// InputTerminal parses the output of the TerminalHandler, without or with colors.
+	InputTerminal
+)
+
+// ReaderConfig configures how the Reader parses log records.
+type ReaderConfig struct {
+	// Format is the expected input format.
+	Format InputFormat
+	// ParseSeparators parses integers with "_" thousand-separators as numbers,
+	// instead of keeping them as strings.
+	ParseSeparators bool
+	// Year is the year of terminal timestamps, which do not include the year.
+	// Zero for the current year.
+	Year int
+	// Location is the time-zone of terminal timestamps, which do not include the time-zone.
+	// Nil for the local time-zone.
+	Location *time.Location
+}
+
+func (cfg *ReaderConfig) Apply(opts ...ReaderOption) {
+	for _, opt := range opts {
+		opt(cfg)
+	}
+}
+
+type ReaderOption func(cfg *ReaderConfig)
+
+// WithInputFormat sets ReaderConfig.Format
+func WithInputFormat(format InputFormat) ReaderOption {
+	return func(cfg *ReaderConfig) {
+		cfg.Format = format
+	}
+}
+
+// WithParseSeparators sets ReaderConfig.ParseSeparators
+func WithParseSeparators(parseSeparators bool) ReaderOption {
+	return func(cfg *ReaderConfig) {
+		cfg.ParseSeparators = parseSeparators
+	}
+}
+
+// WithTerminalTime sets ReaderConfig.Year and ReaderConfig.Location
+func WithTerminalTime(year int, loc *time.Location) ReaderOption {
+	return func(cfg *ReaderConfig) {
+		cfg.Year = year
+		cfg.Location = loc
+	}
+}
+
+// ParseError is returned by Reader.Next when a line cannot be parsed.
+// The Reader can continue with the next line after a ParseError.
+type ParseError struct {
+	Line int
+	Text string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads log records from the output of the LogfmtHandler, JSONHandler or TerminalHandler.
+// Source information is kept as *slog.Source attribute, since records read back do not have a program counter.
+type Reader struct {
+	cfg ReaderConfig
+	sc  *bufio.Scanner
+
+	line    int
+	peeked  bool
+	peekErr error
+	peek    string
+}
+
+const maxReaderLineSize = 1 << 20
+
+// NewReader creates a Reader that parses log records from r, one record per line.
+func NewReader(r io.Reader, opts ...ReaderOption) *Reader {
+	out := &Reader{sc: bufio.NewScanner(r)}
+	out.sc.Buffer(nil, maxReaderLineSize)
+	out.cfg.Apply(opts...)
+	return out
+}
+
+func (r *Reader) nextLine() (string, error) {
+	if r.peeked {
+		r.peeked = false
+		return r.peek, r.peekErr
+	}
+	if !r.sc.Scan() {
+		if err := r.sc.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	r.line++
+	return r.sc.Text(), nil
+}
+
+func (r *Reader) peekLine() (string, error) {
+	if !r.peeked {
+		r.peek, r.peekErr = r.nextLine()
+		r.peeked = true
+	}
+	return r.peek, r.peekErr
+}
+
+// Next reads the next log record.
+// Empty lines are skipped. This returns io.EOF when there are no more records,
+// or a *ParseError if the next line is not a valid log record.
+func (r *Reader) Next() (slog.Record, error) {
+	var line string
+	for {
+		l, err := r.nextLine()
+		if err != nil {
+			return slog.Record{}, err
+		}
+		if strings.TrimSpace(l) != "" {
+			line = l
+			break
+		}
+	}
+	lineNum := r.line
+	format := r.cfg.Format
+	if format == InputAuto {
+		format = detectInputFormat(line)
+	}
+	var (
+		rec parsedRecord
+		err error
+	)
+	switch format {
+	case InputJSON:
+		rec, err = r.cfg.parseJSON(line)
+	case InputLogfmt:
+		rec, err = r.cfg.parseLogfmt(line)
+	case InputTerminal:
+		line = stripANSI(line)
+		rec, err = r.cfg.parseTerminal(line)
+		if err == nil {
+			err = r.readContinuation(&rec)
+		}
+	default:
+		err = fmt.Errorf("unknown input format: %d", format)
+	}
+	if err != nil {
+		return slog.Record{}, &ParseError{Line: lineNum, Text: line, Err: err}
+	}
+	out := slog.NewRecord(rec.time, rec.level, rec.msg, 0)
+	out.AddAttrs(rec.attrs...)
+	return out, nil
+}
+
+// detectInputFormat guesses the format of a line.
+func detectInputFormat(line string) InputFormat {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "{") {
+		return InputJSON
+	}
+	if strings.HasPrefix(line, "t=") || strings.HasPrefix(line, "lvl=") ||
+		strings.HasPrefix(line, "source=") || strings.HasPrefix(line, "msg=") {
+		return InputLogfmt
+	}
+	return InputTerminal
+}
+
+// stripANSI removes ANSI color codes.
+func stripANSI(s string) string {
+	if !strings.Contains(s, "\x1b[") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '[' {
+			j := i + 2
+			for j < len(s) && (s[j] == ';' || (s[j] >= '0' && s[j] <= '9')) {
+				j++
+			}
+			if j < len(s) && s[j] == 'm' {
+				i = j
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+type parsedRecord struct {
+	time  time.Time
+	level slog.Level
+	msg   string
+	attrs []slog.Attr
+}
+
+// builtin processes the builtin keys of the logfmt and JSON formats,
+// and reports whether the attribute was builtin.
+func (rec *parsedRecord) builtin(key string, v slog.Value) (bool, error) {
+	switch key {
+	case "t":
+		if v.Kind() == slog.KindTime {
+			rec.time = v.Time()
+			return true, nil
+		}
+		t, err := parseTime(v.String())
+		if err != nil {
+			return true, err
+		}
+		rec.time = t
+	case "lvl":
+		lvl, err := LevelFromString(v.String())
+		if err != nil {
+			return true, err
+		}
+		rec.level = lvl
+	case "msg":
+		rec.msg = v.String()
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(timeFormat, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+// parseSource parses a "file:line" source location, optionally followed by the function name.
+func parseSource(s string) (*slog.Source, bool) {
+	if i := strings.LastIndexByte(s, ' '); i >= 0 {
+		if src, ok := parseSource(s[:i]); ok {
+			src.Function = s[i+1:]
+			return src, true
+		}
+	}
+	i := strings.LastIndexByte(s, ':')
+	if i < 0 {
+		return nil, false
+	}
+	line, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return nil, false
+	}
+	return &slog.Source{File: s[:i], Line: line}, true
+}
+
+func (cfg *ReaderConfig) parseLogfmt(line string) (rec parsedRecord, err error) {
+	pairs, err := splitKeyValues(line)
+	if err != nil {
+		return rec, err
+	}
+	rec.level = LevelInfo
+	for _, p := range pairs {
+		v := cfg.parseValue(p.value, p.quoted)
+		if ok, err := rec.builtin(p.key, v); err != nil {
+			return rec, fmt.Errorf("invalid %q value: %w", p.key, err)
+		} else if ok {
+			continue
+		}
+		if p.key == slog.SourceKey {
+			if src, ok := parseSource(p.value); ok {
+				rec.attrs = append(rec.attrs, slog.Any(slog.SourceKey, src))
+				continue
+			}
+		}
+		if p.key == sourceFuncKey && len(rec.attrs) > 0 {
+			// the function of the preceding source attribute, see WithSourceFunc
+			if src, ok := rec.attrs[len(rec.attrs)-1].Value.Any().(*slog.Source); ok && src.Function == "" {
+				src.Function = p.value
+				continue
+			}
+		}
+		rec.attrs = append(rec.attrs, slog.Attr{Key: p.key, Value: v})
+	}
+	return rec, nil
+}
+
+func (cfg *ReaderConfig) parseJSON(line string) (rec parsedRecord, err error) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	attrs, err := cfg.decodeJSONObject(dec)
+	if err != nil {
+		return rec, err
+	}
+	rec.level = LevelInfo
+	for _, a := range attrs {
+		if a.Key == "t" && a.Value.Kind() == slog.KindString {
+			t, err := parseTime(a.Value.String())
+			if err != nil {
+				return rec, fmt.Errorf("invalid time: %w", err)
+			}
+			rec.time = t
+			continue
+		}
+		if ok, err := rec.builtin(a.Key, a.Value); err != nil {
+			return rec, fmt.Errorf("invalid %q value: %w", a.Key, err)
+		} else if ok {
+			continue
+		}
+		if a.Key == slog.SourceKey && a.Value.Kind() == slog.KindGroup {
+			src := new(slog.Source)
+			for _, field := range a.Value.Group() {
+				switch field.Key {
+				case "function":
+					src.Function = field.Value.String()
+				case "file":
+					src.File = field.Value.String()
+				case "line":
+					src.Line = int(field.Value.Int64())
+				}
+			}
+			a = slog.Any(slog.SourceKey, src)
+		}
+		rec.attrs = append(rec.attrs, a)
+	}
+	return rec, nil
+}
+
+// decodeJSONObject decodes the next JSON object as list of attributes, preserving the order of keys.
+// Nested objects are decoded as groups.
+func (cfg *ReaderConfig) decodeJSONObject(dec *json.Decoder) ([]slog.Attr, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected JSON object, got %v", tok)
+	}
+	var attrs []slog.Attr
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected JSON key, got %v", tok)
+		}
+		v, err := cfg.decodeJSONValue(dec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %q value: %w", key, err)
+		}
+		attrs = append(attrs, slog.Attr{Key: key, Value: v})
+	}
+	if _, err := dec.Token(); err != nil { // closing '}'
+		return nil, err
+	}
+	return attrs, nil
+}
+
+func (cfg *ReaderConfig) decodeJSONValue(dec *json.Decoder) (slog.Value, error) {
+	if !dec.More() {
+		return slog.Value{}, errors.New("missing value")
+	}
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return slog.Value{}, err
+	}
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) > 0 && raw[0] == '{':
+		inner := json.NewDecoder(bytes.NewReader(raw))
+		inner.UseNumber()
+		attrs, err := cfg.decodeJSONObject(inner)
+		if err != nil {
+			return slog.Value{}, err
+		}
+		return slog.GroupValue(attrs...), nil
+	case len(raw) > 0 && raw[0] == '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return slog.Value{}, err
+		}
+		return slog.StringValue(s), nil
+	case len(raw) > 0 && raw[0] == '[':
+		var x []any
+		if err := json.Unmarshal(raw, &x); err != nil {
+			return slog.Value{}, err
+		}
+		return slog.AnyValue(x), nil
+	case string(raw) == "null":
+		return slog.AnyValue(nil), nil
+	default:
+		// numbers and booleans
+		return cfg.parseValue(string(raw), false), nil
+	}
+}
+
+// parseTerminal parses a record line of the TerminalHandler:
+//
+//	[source:line] LEVEL[[TIME]] MESSAGE key=value key=value ...
+func (cfg *ReaderConfig) parseTerminal(line string) (rec parsedRecord, err error) {
+	// optional source prefix, right-padded
+	rest := line
+	if lvlIdx := terminalLevelIndex(rest); lvlIdx > 0 {
+		src := strings.TrimSpace(rest[:lvlIdx])
+		if s, ok := parseSource(src); ok {
+			rec.attrs = append(rec.attrs, slog.Any(slog.SourceKey, s))
+		}
+		rest = rest[lvlIdx:]
+	} else if lvlIdx < 0 {
+		return rec, errors.New("missing level")
+	}
+	if len(rest) < 5 {
+		return rec, errors.New("missing level")
+	}
+	rec.level, err = LevelFromString(strings.TrimSpace(rest[:5]))
+	if err != nil {
+		return rec, err
+	}
+	rest = rest[5:]
+	if strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return rec, errors.New("unterminated timestamp")
+		}
+		rec.time, err = cfg.parseTerminalTime(rest[1:end])
+		if err != nil {
+			return rec, err
+		}
+		rest = rest[end+1:]
+	}
+	rest = strings.TrimPrefix(rest, " ")
+
+	// The message is quoted if it contains a '=', so the attributes start with the first unquoted "key="
+	if strings.HasPrefix(rest, `"`) {
+		end := quotedEnd(rest)
+		if end < 0 {
+			return rec, errors.New("unterminated message")
+		}
+		rec.msg, err = strconv.Unquote(rest[:end])
+		if err != nil {
+			return rec, err
+		}
+		rest = rest[end:]
+	} else if eq := strings.IndexByte(rest, '='); eq < 0 {
+		rec.msg = strings.TrimRight(rest, " ")
+		rest = ""
+	} else {
+		keyStart := strings.LastIndexByte(rest[:eq], ' ')
+		if keyStart < 0 {
+			return rec, errors.New("missing message")
+		}
+		rec.msg = strings.TrimRight(rest[:keyStart], " ")
+		rest = rest[keyStart:]
+	}
+	pairs, err := splitKeyValues(rest)
+	if err != nil {
+		return rec, err
+	}
+	for _, p := range pairs {
+		rec.attrs = append(rec.attrs, slog.Attr{Key: p.key, Value: cfg.parseValue(p.value, p.quoted)})
+	}
+	return rec, nil
+}
+
+// terminalLevelIndex returns the index of the level in a terminal line, or -1 if not found.
+func terminalLevelIndex(line string) int {
+	best := -1
+	for _, lvl := range []slog.Level{LevelTrace, LevelDebug, LevelInfo, LevelWarn, LevelError, LevelCrit} {
+		name := LevelAlignedString(lvl)
+		for i := 0; ; {
+			j := strings.Index(line[i:], name)
+			if j < 0 {
+				break
+			}
+			j += i
+			// the level is at the start, or follows the padded source
+			if j == 0 || line[j-1] == ' ' {
+				if best < 0 || j < best {
+					best = j
+				}
+				break
+			}
+			i = j + 1
+		}
+	}
+	return best
+}
+
+// parseTerminalTime parses the "01-02|15:04:05.000" terminal time format.
+func (cfg *ReaderConfig) parseTerminalTime(s string) (time.Time, error) {
+	loc := cfg.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t, err := time.ParseInLocation("01-02|15:04:05.000", s, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	year := cfg.Year
+	if year == 0 {
+		year = time.Now().In(loc).Year()
+	}
+	return t.AddDate(year-t.Year(), 0, 0), nil
+}
+
+// readContinuation reads the multi-line message and attribute blocks below a terminal record line.
+func (r *Reader) readContinuation(rec *parsedRecord) error {
+	placeholders := make(map[string]int)
+	for i, a := range rec.attrs {
+		if a.Value.Kind() == slog.KindString && isLinesPlaceholder(a.Value.String()) {
+			placeholders[a.Key] = i
+		}
+	}
+	blockAttr := -1
+	var lines []string
+	flush := func() {
+		if blockAttr >= 0 {
+			rec.attrs[blockAttr].Value = slog.StringValue(strings.Join(lines, "\n"))
+		}
+		lines = lines[:0]
+	}
+	defer flush()
+	for {
+		line, err := r.peekLine()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		line = stripANSI(line)
+		if !strings.HasPrefix(line, termContinuation) {
+			return nil
+		}
+		r.peeked = false // consume the line
+		content := line[len(termContinuation):]
+		if blockAttr >= 0 && strings.HasPrefix(content, "  ") {
+			lines = append(lines, unquoteMessage(content[2:]))
+			continue
+		}
+		if key, ok := strings.CutSuffix(content, ":"); ok {
+			if i, ok := placeholders[key]; ok {
+				flush()
+				blockAttr = i
+				continue
+			}
+		}
+		if blockAttr < 0 {
+			rec.msg += "\n" + unquoteMessage(content)
+		}
+	}
+}
+
+// linesPlaceholderSuffix ends the "<N lines>" placeholder of a multi-line terminal value.
+const linesPlaceholderSuffix = " lines>"
+
+// isLinesPlaceholder checks if s is the "<N lines>" placeholder of a multi-line terminal value.
+func isLinesPlaceholder(s string) bool {
+	n, ok := strings.CutPrefix(s, "<")
+	if !ok {
+		return false
+	}
+	n, ok = strings.CutSuffix(n, linesPlaceholderSuffix)
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(n)
+	return err == nil
+}
+
+// unquoteMessage reverses escapeMessage.
+func unquoteMessage(s string) string {
+	if strings.HasPrefix(s, `"`) {
+		if v, err := strconv.Unquote(s); err == nil {
+			return v
+		}
+	}
+	return s
+}
+
+type keyValue struct {
+	key    string
+	value  string
+	quoted bool
+}
+
+// splitKeyValues splits a logfmt-style list of key=value pairs.
+// Quoted keys and values are unquoted.
+func splitKeyValues(s string) ([]keyValue, error) {
+	var out []keyValue
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return out, nil
+		}
+		var kv keyValue
+		if s[0] == '"' {
+			end := quotedEnd(s)
+			if end < 0 {
+				return nil, errors.New("unterminated quoted key")
+			}
+			key, err := strconv.Unquote(s[:end])
+			if err != nil {
+				return nil, err
+			}
+			kv.key = key
+			s = s[end:]
+		} else {
+			end := strings.IndexAny(s, "= ")
+			if end < 0 {
+				end = len(s)
+			}
+			kv.key = s[:end]
+			s = s[end:]
+		}
+		if !strings.HasPrefix(s, "=") {
+			return nil, fmt.Errorf("missing value of key %q", kv.key)
+		}
+		s = s[1:]
+		if strings.HasPrefix(s, `"`) {
+			end := quotedEnd(s)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated value of key %q", kv.key)
+			}
+			v, err := strconv.Unquote(s[:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of key %q: %w", kv.key, err)
+			}
+			kv.value, kv.quoted = v, true
+			s = s[end:]
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end < 0 {
+				end = len(s)
+			}
+			// the "<N lines>" placeholder of multi-line terminal values is not quoted, but contains a space
+			if i := strings.Index(s, linesPlaceholderSuffix); i > 0 && isLinesPlaceholder(s[:i+len(linesPlaceholderSuffix)]) {
+				end = i + len(linesPlaceholderSuffix)
+			}
+			kv.value = s[:end]
+			s = s[end:]
+		}
+		out = append(out, kv)
+	}
+}
+
+// quotedEnd returns the index after the closing quote of the quoted string at the start of s,
+// or -1 if the string is not terminated.
+func quotedEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// parseValue infers the type of an unquoted value. Quoted values are always strings.
+func (cfg *ReaderConfig) parseValue(s string, quoted bool) slog.Value {
+	if quoted || s == "" {
+		return slog.StringValue(s)
+	}
+	switch s {
+	case "true":
+		return slog.BoolValue(true)
+	case "false":
+		return slog.BoolValue(false)
+	}
+	if c := s[0]; !(c >= '0' && c <= '9') && c != '-' && c != '+' && c != '.' {
+		return slog.StringValue(s)
+	}
+	digits := s
+	if cfg.ParseSeparators && isSeparatedInt(s) {
+		digits = strings.ReplaceAll(s, "_", "")
+	}
+	if v, err := strconv.ParseInt(digits, 10, 64); err == nil {
+		return slog.Int64Value(v)
+	}
+	if v, err := strconv.ParseUint(digits, 10, 64); err == nil {
+		return slog.Uint64Value(v)
+	}
+	if v, ok := new(big.Int).SetString(digits, 10); ok {
+		return slog.AnyValue(v)
+	}
+	// ParseFloat accepts "_" separators too, these are only parsed if enabled
+	if v, err := strconv.ParseFloat(s, 64); err == nil && !strings.Contains(s, "_") {
+		return slog.Float64Value(v)
+	}
+	if v, err := time.Parse(timeFormat, s); err == nil {
+		return slog.TimeValue(v)
+	}
+	if v, err := time.ParseDuration(s); err == nil {
+		return slog.DurationValue(v)
+	}
+	return slog.StringValue(s)
+}
+
+// isSeparatedInt checks if s is an integer with "_" separators between every 3 digits.
+func isSeparatedInt(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	groups := strings.Split(s, "_")
+	if len(groups) < 2 || len(groups[0]) == 0 || len(groups[0]) > 3 {
+		return false
+	}
+	for i, g := range groups {
+		if i > 0 && len(g) != 3 {
+			return false
+		}
+		for _, c := range g {
+			if c < '0' || c > '9' {
+				return false
+			}
+		}
+	}
+	return true
+}
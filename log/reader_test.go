@@ -0,0 +1,99 @@
+package log_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func readAll(t *testing.T, r *log.Reader) []slog.Record {
+	var out []slog.Record
+	for {
+		rec, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return out
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, rec)
+	}
+}
+
+func recordAttrs(r slog.Record) map[string]slog.Value {
+	out := make(map[string]slog.Value)
+	r.Attrs(func(a slog.Attr) bool {
+		out[a.Key] = a.Value
+		return true
+	})
+	return out
+}
+
+func TestReader(t *testing.T) {
+	handlers := map[string]func(w io.Writer) slog.Handler{
+		"logfmt": func(w io.Writer) slog.Handler {
+			return log.LogfmtHandler(w, log.WithIncludeSource(true))
+		},
+		"json": func(w io.Writer) slog.Handler {
+			return log.JSONHandler(w, log.WithIncludeSource(true))
+		},
+		"terminal": func(w io.Writer) slog.Handler {
+			return log.TerminalHandler(w, log.WithColor(true), log.WithIncludeSource(true), log.WithMultiLine(true))
+		},
+	}
+	for name, fn := range handlers {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := log.New(fn(&buf))
+			start := time.Now().Truncate(time.Second)
+			logger.Warn("hello world", "a", 1, "b", "x y", "c", uint64(1234567), "d", 1.5, "e", true)
+			logger.Error("second\nline", "stack", "foo\nbar")
+			logger.Crit("key=value in message")
+
+			recs := readAll(t, log.NewReader(&buf, log.WithParseSeparators(true)))
+			assertEqual(t, len(recs), 3)
+
+			r := recs[0]
+			assertEqual(t, r.Level, log.LevelWarn)
+			assertEqual(t, r.Message, "hello world")
+			assertTrue(t, !r.Time.Before(start))
+			attrs := recordAttrs(r)
+			assertEqual(t, attrs["a"].Int64(), 1)
+			assertEqual(t, attrs["b"].String(), "x y")
+			assertEqual(t, attrs["c"].Int64(), 1234567)
+			assertEqual(t, attrs["d"].Float64(), 1.5)
+			assertEqual(t, attrs["e"].Bool(), true)
+			src, ok := attrs[slog.SourceKey].Any().(*slog.Source)
+			assertTrue(t, ok)
+			assertSubstring(t, src.File, "reader_test.go")
+
+			r = recs[1]
+			assertEqual(t, r.Level, log.LevelError)
+			assertEqual(t, r.Message, "second\nline")
+			assertEqual(t, recordAttrs(r)["stack"].String(), "foo\nbar")
+
+			assertEqual(t, recs[2].Level, log.LevelCrit)
+			assertEqual(t, recs[2].Message, "key=value in message")
+		})
+	}
+}
+
+func TestReaderParseError(t *testing.T) {
+	input := "lvl=info msg=ok\nlvl=bogus msg=bad\n\nlvl=warn msg=after"
+	r := log.NewReader(strings.NewReader(input))
+	_, err := r.Next()
+	assertTrue(t, err == nil)
+	_, err = r.Next()
+	var parseErr *log.ParseError
+	assertTrue(t, errors.As(err, &parseErr))
+	assertEqual(t, parseErr.Line, 2)
+	rec, err := r.Next()
+	assertTrue(t, err == nil)
+	assertEqual(t, rec.Message, "after")
+}
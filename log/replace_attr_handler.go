@@ -0,0 +1,101 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// ReplaceAttrFunc rewrites an attribute, like slog.HandlerOptions.ReplaceAttr.
+// The groups are the names of the groups that qualify the attribute, outermost first.
+// The function is not called for group attributes, but for each of the attributes within them.
+// Attributes with an empty key after replacement are dropped.
+type ReplaceAttrFunc func(groups []string, a slog.Attr) slog.Attr
+
+// ReplaceAttrHandler rewrites the attributes of log records and of WithAttrs,
+// before they reach the inner handler. The builtin time, level, message and source
+// fields of a record are not attributes, and are left as-is.
+type ReplaceAttrHandler struct {
+	inner  slog.Handler
+	fn     ReplaceAttrFunc
+	groups []string
+}
+
+var _ Handler = (*ReplaceAttrHandler)(nil)
+
+func ReplaceAttrMod(fn ReplaceAttrFunc) HandlerMod {
+	return func(h slog.Handler) slog.Handler {
+		return &ReplaceAttrHandler{inner: h, fn: fn}
+	}
+}
+
+func (h *ReplaceAttrHandler) Unwrap() slog.Handler {
+	return h.inner
+}
+
+func (h *ReplaceAttrHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *ReplaceAttrHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.NumAttrs() == 0 {
+		return h.inner.Handle(ctx, r)
+	}
+	out := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		if a = h.replace(h.groups, a); a.Key != "" || a.Value.Kind() == slog.KindGroup {
+			out.AddAttrs(a)
+		}
+		return true
+	})
+	return h.inner.Handle(ctx, out)
+}
+
+// WithAttrs replaces the attributes once, and passes the result to the inner handler,
+// so the replacement is not repeated for every log record.
+func (h *ReplaceAttrHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	replaced := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if a = h.replace(h.groups, a); a.Key != "" || a.Value.Kind() == slog.KindGroup {
+			replaced = append(replaced, a)
+		}
+	}
+	return &ReplaceAttrHandler{
+		inner:  h.inner.WithAttrs(replaced),
+		fn:     h.fn,
+		groups: h.groups,
+	}
+}
+
+func (h *ReplaceAttrHandler) WithGroup(name string) slog.Handler {
+	return &ReplaceAttrHandler{
+		inner:  h.inner.WithGroup(name),
+		fn:     h.fn,
+		groups: append(slices.Clip(h.groups), name),
+	}
+}
+
+// replace applies the replacement function to the attribute,
+// or to each of the attributes within it, if it is a group.
+// Inline groups, with an empty key, do not qualify their attributes.
+func (h *ReplaceAttrHandler) replace(groups []string, a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		return h.fn(groups, a)
+	}
+	inner := groups
+	if a.Key != "" {
+		inner = append(slices.Clip(groups), a.Key)
+	}
+	members := a.Value.Group()
+	replaced := make([]slog.Attr, 0, len(members))
+	for _, m := range members {
+		if m = h.replace(inner, m); m.Key != "" || m.Value.Kind() == slog.KindGroup {
+			replaced = append(replaced, m)
+		}
+	}
+	if len(replaced) == 0 {
+		return slog.Attr{} // empty groups are dropped
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(replaced...)}
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestReplaceAttrMod(t *testing.T) {
+	var calls []string
+	redact := ReplaceAttrMod(func(groups []string, a slog.Attr) slog.Attr {
+		path := strings.Join(append(groups, a.Key), ".")
+		calls = append(calls, path)
+		switch path {
+		case "peer.secret":
+			return slog.String(a.Key, "***")
+		case "peer.drop":
+			return slog.Attr{}
+		}
+		return a
+	})
+	logger := New(JSONHandler(io.Discard), CapturingMod(), redact)
+	c, ok := FindHandler[*CapturingHandler](logger.Handler())
+	if !ok {
+		t.Fatal("expected capturing handler")
+	}
+
+	sub := logger.With("a", 1).WithGroup("peer").With("secret", "hunter2")
+	sub.Info("first", "drop", true, slog.Group("inner", "x", 1))
+	sub.Info("second")
+	if got := strings.Join(calls, ","); got != "a,peer.secret,peer.drop,peer.inner.x" {
+		t.Fatalf("unexpected replacement calls: %s", got)
+	}
+
+	recs := c.FindLogs()
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+	if got := recs[1].AttrValue("secret"); got != "***" {
+		t.Fatalf("expected redacted With attribute, got %v", got)
+	}
+	if got := recs[0].AttrValue("drop"); got != nil {
+		t.Fatalf("expected dropped attribute, got %v", got)
+	}
+	if _, ok := lookupAttr(recs[0], "inner.x"); !ok {
+		t.Fatal("expected attribute in group to be kept")
+	}
+}
@@ -0,0 +1,189 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+	"sync/atomic"
+)
+
+// Route dispatches log records within a level range, that match an optional filter, to a handler.
+type Route struct {
+	// MinLevel and MaxLevel are the inclusive level range of the route.
+	MinLevel slog.Level
+	MaxLevel slog.Level
+	// Filter optionally restricts the route to matching records.
+	// The record includes the attributes that the router was derived with,
+	// and attributes are nested in the groups of the logger, like in the handler output.
+	// E.g. a "req.id==abc" query matches logger.WithGroup("req").Info("hello", "id", "abc").
+	Filter LogFilter
+	// Handler handles the records of the route.
+	Handler slog.Handler
+}
+
+// LevelRoute routes the records within the inclusive level range to h.
+func LevelRoute(h slog.Handler, minLvl, maxLvl slog.Level) Route {
+	return Route{MinLevel: minLvl, MaxLevel: maxLvl, Handler: h}
+}
+
+// MinLevelRoute routes the records at or above the level to h.
+func MinLevelRoute(h slog.Handler, minLvl slog.Level) Route {
+	return LevelRoute(h, minLvl, math.MaxInt)
+}
+
+// FilterRoute routes the records that match the filter to h.
+func FilterRoute(h slog.Handler, filter LogFilter) Route {
+	return Route{MinLevel: math.MinInt, MaxLevel: math.MaxInt, Filter: filter, Handler: h}
+}
+
+func (r *Route) inRange(lvl slog.Level) bool {
+	return r.MinLevel <= lvl && lvl <= r.MaxLevel
+}
+
+// RouteHandler dispatches each log record to every matching route.
+// Records that match no route are handled by the inner handler.
+// The routes are shared among derived RouteHandlers, and can be replaced at runtime with SetRoutes.
+type RouteHandler struct {
+	inner  slog.Handler
+	routes *atomic.Pointer[[]Route] // shared among derived RouteHandlers
+	// scope is replayed on the route handlers, to derive them like the inner handler
+	scope *HandlerScope
+	// attrs are inherited log record attributes, nested in their groups, for route filters
+	attrs *CapturedAttrs
+	// groups are the open groups, that qualify the record attributes for route filters
+	groups []string
+	// derived caches the route handlers, derived with the scope
+	derived atomic.Pointer[derivedRoutes]
+}
+
+// derivedRoutes are the route handlers, derived from a particular set of routes.
+type derivedRoutes struct {
+	src      *[]Route
+	handlers []slog.Handler
+}
+
+var _ Handler = (*RouteHandler)(nil)
+
+func RouteMod(routes ...Route) HandlerMod {
+	return func(h slog.Handler) slog.Handler {
+		out := &RouteHandler{inner: h, routes: new(atomic.Pointer[[]Route])}
+		out.SetRoutes(routes...)
+		return out
+	}
+}
+
+func (h *RouteHandler) Unwrap() slog.Handler {
+	return h.inner
+}
+
+// Routes returns a copy of the current routes.
+func (h *RouteHandler) Routes() []Route {
+	return append([]Route(nil), *h.routes.Load()...)
+}
+
+// SetRoutes replaces the routes, of this handler and all handlers that share its routes.
+func (h *RouteHandler) SetRoutes(routes ...Route) {
+	routes = append([]Route(nil), routes...)
+	h.routes.Store(&routes)
+}
+
+// current returns the current routes, and the route handlers derived with the scope of h.
+func (h *RouteHandler) current() ([]Route, []slog.Handler) {
+	src := h.routes.Load()
+	if d := h.derived.Load(); d != nil && d.src == src {
+		return *src, d.handlers
+	}
+	handlers := make([]slog.Handler, len(*src))
+	for i, route := range *src {
+		handlers[i] = h.scope.apply(route.Handler)
+	}
+	h.derived.Store(&derivedRoutes{src: src, handlers: handlers})
+	return *src, handlers
+}
+
+func (h *RouteHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	routes, handlers := h.current()
+	for i := range routes {
+		if routes[i].inRange(lvl) && handlers[i].Enabled(ctx, lvl) {
+			return true
+		}
+	}
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *RouteHandler) Handle(ctx context.Context, r slog.Record) error {
+	routes, handlers := h.current()
+	var errs []error
+	routed := false
+	var filterRec *CapturedRecord
+	for i := range routes {
+		route := &routes[i]
+		if !route.inRange(r.Level) {
+			continue
+		}
+		if route.Filter != nil {
+			if filterRec == nil {
+				filterRec = h.filterRecord(&r)
+			}
+			if !route.Filter(filterRec) {
+				continue
+			}
+		}
+		routed = true
+		if handlers[i].Enabled(ctx, r.Level) {
+			errs = append(errs, handlers[i].Handle(ctx, r.Clone()))
+		}
+	}
+	if !routed && h.inner.Enabled(ctx, r.Level) {
+		errs = append(errs, h.inner.Handle(ctx, r))
+	}
+	return errors.Join(errs...)
+}
+
+// filterRecord returns the record as seen by route filters, with the attributes nested in the open groups.
+func (h *RouteHandler) filterRecord(r *slog.Record) *CapturedRecord {
+	if len(h.groups) == 0 {
+		return &CapturedRecord{Parent: h.attrs, Record: r}
+	}
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	grouped := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	grouped.AddAttrs(nestInGroups(h.groups, attrs)...)
+	return &CapturedRecord{Parent: h.attrs, Record: &grouped}
+}
+
+// nestInGroups nests the attributes in the groups, outermost first.
+func nestInGroups(groups []string, attrs []slog.Attr) []slog.Attr {
+	for i := len(groups) - 1; i >= 0 && len(attrs) > 0; i-- {
+		attrs = []slog.Attr{{Key: groups[i], Value: slog.GroupValue(attrs...)}}
+	}
+	return attrs
+}
+
+func (h *RouteHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &RouteHandler{
+		inner:  h.inner.WithAttrs(attrs),
+		routes: h.routes,
+		scope:  h.scope.withAttrs(attrs),
+		attrs: &CapturedAttrs{
+			Parent:     h.attrs,
+			Attributes: nestInGroups(h.groups, attrs),
+		},
+		groups: h.groups,
+	}
+}
+
+func (h *RouteHandler) WithGroup(name string) slog.Handler {
+	return &RouteHandler{
+		inner:  h.inner.WithGroup(name),
+		routes: h.routes,
+		scope:  h.scope.withGroup(name),
+		attrs:  h.attrs,
+		groups: slices.Concat(h.groups, []string{name}),
+	}
+}
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRouteMod(t *testing.T) {
+	var stdout, stderr, alerts bytes.Buffer
+	logger := New(LogfmtHandler(&stdout, WithExcludeTime(true)),
+		LevelMod(LevelDebug),
+		RouteMod(
+			MinLevelRoute(LogfmtHandler(&stderr, WithExcludeTime(true)), LevelError),
+			FilterRoute(LogfmtHandler(&alerts, WithExcludeTime(true)), MustParseLogFilter("lvl>=error && alert")),
+		))
+	sub := logger.With("alert", true).WithGroup("g")
+	logger.Info("hello")
+	logger.Error("oops")
+	sub.Crit("fire", "x", 1)
+
+	if got := stdout.String(); got != "lvl=info msg=hello\n" {
+		t.Fatalf("unexpected stdout: %q", got)
+	}
+	if got := stderr.String(); got != "lvl=error msg=oops\nlvl=crit msg=fire alert=true g.x=1\n" {
+		t.Fatalf("unexpected stderr: %q", got)
+	}
+	if got := alerts.String(); got != "lvl=crit msg=fire alert=true g.x=1\n" {
+		t.Fatalf("unexpected alerts: %q", got)
+	}
+
+	router, ok := FindHandler[*RouteHandler](sub.Handler())
+	if !ok {
+		t.Fatal("expected to find router")
+	}
+	var replaced bytes.Buffer
+	router.SetRoutes(LevelRoute(LogfmtHandler(&replaced, WithExcludeTime(true)), LevelWarn, LevelWarn))
+	sub.Warn("replaced", "y", 2)
+	logger.Error("fallback")
+	if got := replaced.String(); got != "lvl=warn msg=replaced alert=true g.y=2\n" {
+		t.Fatalf("unexpected output of replaced route: %q", got)
+	}
+	if !strings.HasSuffix(stdout.String(), "lvl=error msg=fallback\n") {
+		t.Fatalf("expected unrouted record on inner handler, got %q", stdout.String())
+	}
+}
+
+func TestRouteModGroups(t *testing.T) {
+	var stdout, routed bytes.Buffer
+	logger := New(LogfmtHandler(&stdout, WithExcludeTime(true)),
+		RouteMod(FilterRoute(LogfmtHandler(&routed, WithExcludeTime(true)), MustParseLogFilter("req.id==abc || req.peer.id==def"))))
+	req := logger.WithGroup("req")
+	req.Info("record attr", "id", "abc")
+	req.With("id", "abc").Info("logger attr")
+	req.WithGroup("peer").Info("nested group", "id", "def")
+	req.Info("other", "id", "xyz")
+	logger.Info("ungrouped", "id", "abc")
+	expected := `lvl=info msg="record attr" req.id=abc
+lvl=info msg="logger attr" req.id=abc
+lvl=info msg="nested group" req.peer.id=def
+`
+	if got := routed.String(); got != expected {
+		t.Fatalf("unexpected routed records: %q", got)
+	}
+	if got := stdout.String(); got != "lvl=info msg=other req.id=xyz\nlvl=info msg=ungrouped id=abc\n" {
+		t.Fatalf("unexpected unrouted records: %q", got)
+	}
+}
+
+func TestRouteModEnabled(t *testing.T) {
+	h := RouteMod(LevelRoute(DiscardHandler(), LevelWarn, LevelError))(LevelMod(LevelCrit)(DiscardHandler()))
+	if h.Enabled(context.Background(), LevelInfo) {
+		t.Fatal("expected info to be disabled")
+	}
+	var buf bytes.Buffer
+	h = RouteMod(LevelRoute(LogfmtHandler(&buf), LevelWarn, LevelError))(DiscardHandler())
+	if !h.Enabled(context.Background(), slog.LevelWarn) || h.Enabled(context.Background(), LevelInfo) {
+		t.Fatal("expected route to enable the levels of its range")
+	}
+}
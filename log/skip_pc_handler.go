@@ -0,0 +1,43 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SkipPCHandler marks a handler stack to not capture the program counter of log calls.
+// Loggers created with this handler in the stack skip the runtime.Callers work,
+// and records do not have source information.
+type SkipPCHandler struct {
+	inner slog.Handler
+}
+
+var _ Handler = (*SkipPCHandler)(nil)
+
+// SkipPCMod disables the program-counter capture of the logger, for faster logging,
+// at the cost of not having source information.
+func SkipPCMod() HandlerMod {
+	return func(h slog.Handler) slog.Handler {
+		return &SkipPCHandler{inner: h}
+	}
+}
+
+func (h *SkipPCHandler) Unwrap() slog.Handler {
+	return h.inner
+}
+
+func (h *SkipPCHandler) Enabled(ctx context.Context, lvl slog.Level) bool {
+	return h.inner.Enabled(ctx, lvl)
+}
+
+func (h *SkipPCHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *SkipPCHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SkipPCHandler{inner: h.inner.WithAttrs(attrs)}
+}
+
+func (h *SkipPCHandler) WithGroup(name string) slog.Handler {
+	return &SkipPCHandler{inner: h.inner.WithGroup(name)}
+}
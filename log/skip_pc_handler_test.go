@@ -0,0 +1,42 @@
+package log
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestSkipPCMod(t *testing.T) {
+	logger := New(JSONHandler(io.Discard), CapturingMod())
+	c, _ := FindHandler[*CapturingHandler](logger.Handler())
+	logger.Info("with pc")
+	if rec := c.FindLog(); rec == nil || rec.PC == 0 {
+		t.Fatal("expected program counter")
+	}
+
+	logger = New(JSONHandler(io.Discard), CapturingMod(), SkipPCMod())
+	c, _ = FindHandler[*CapturingHandler](logger.Handler())
+	logger.With("a", 1).Info("without pc")
+	if rec := c.FindLog(); rec == nil || rec.PC != 0 {
+		t.Fatal("expected no program counter")
+	}
+}
+
+type ctxKey struct{}
+
+func TestLoggerWithContext(t *testing.T) {
+	logger := New(JSONHandler(io.Discard), CapturingMod())
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	sub := logger.WithContext(ctx)
+	if sub.Context() != ctx {
+		t.Fatal("expected new default context")
+	}
+	if logger.Context() != context.Background() {
+		t.Fatal("expected original logger to keep its default context")
+	}
+	c, _ := FindHandler[*CapturingHandler](sub.Handler())
+	sub.Info("hello")
+	if c.FindLog() == nil {
+		t.Fatal("expected derived logger to share the handler stack")
+	}
+}
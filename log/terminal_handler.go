@@ -5,16 +5,16 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"slices"
 	"sync"
 )
 
-type terminalHandler struct {
+// TerminalState is shared by terminal handlers that write to the same output,
+// so that writes do not interleave, and attributes align across handlers.
+// Handlers derived with WithAttrs always share the state of their parent handler.
+// Use WithSharedState to share the state with other TerminalHandlers.
+type TerminalState struct {
 	mu sync.Mutex
-	wr io.Writer
-
-	cfg *FormatConfig
-
-	attrs []slog.Attr
 
 	// fieldPadding is a map with maximum field value lengths seen until now
 	// to allow padding log contexts in a bit smarter way.
@@ -23,6 +23,30 @@ type terminalHandler struct {
 	buf *bytes.Buffer
 }
 
+// NewTerminalState returns a new TerminalState, to share between TerminalHandlers.
+func NewTerminalState() *TerminalState {
+	return &TerminalState{fieldPadding: make(map[string]int)}
+}
+
+// FieldPaddingResetter is a handler that aligns attributes, and can reset the alignment.
+// The TerminalHandler implements this.
+type FieldPaddingResetter interface {
+	slog.Handler
+	ResetFieldPadding()
+}
+
+var _ FieldPaddingResetter = (*terminalHandler)(nil)
+
+type terminalHandler struct {
+	wr io.Writer
+
+	cfg *FormatConfig
+
+	attrs []slog.Attr
+
+	*TerminalState
+}
+
 // TerminalHandler returns a handler which formats log records at all levels optimized for human readability on
 // a terminal with color-coded level output and terser human friendly timestamp.
 // This format should only be used for interactive programs or while developing.
@@ -34,16 +58,29 @@ type terminalHandler struct {
 //	[DBUG] [May 16 20:58:45] remove route ns=haproxy addr=127.0.0.1:50002
 func TerminalHandler(wr io.Writer, opts ...FormatOption) slog.Handler {
 	out := &terminalHandler{
-		wr:           wr,
-		fieldPadding: make(map[string]int),
+		wr: wr,
 		cfg: &FormatConfig{
-			UseColor:      false,
-			IncludeSource: false,
-			ExcludeTime:   false,
-			SourceRelDir:  "",
+			UseColor:          false,
+			IncludeSource:     false,
+			ExcludeTime:       false,
+			SourceRelDir:      "",
+			SourcePadding:     defaultSourcePadding,
+			ThousandSeparator: defaultFormatConfig.ThousandSeparator,
+			FloatPrecision:    defaultFormatConfig.FloatPrecision,
 		},
 	}
 	out.cfg.Apply(opts...)
+	out.TerminalState = out.cfg.SharedState
+	if out.TerminalState == nil {
+		out.TerminalState = NewTerminalState()
+	}
+	if out.cfg.AutoColor {
+		support := DetectColorSupport(wr)
+		out.cfg.UseColor = support != ColorSupportNone
+		if out.cfg.Theme == nil {
+			out.cfg.Theme = ThemeFor(support)
+		}
+	}
 	return out
 }
 
@@ -52,7 +89,11 @@ func (h *terminalHandler) Handle(_ context.Context, r slog.Record) error {
 	defer h.mu.Unlock()
 	buf := h.format(r)
 	h.wr.Write(buf)
-	h.buf.Reset()
+	if h.buf.Cap() > termMaxBufferSize {
+		h.buf = nil // don't hold on to the memory of exceptionally large records
+	} else {
+		h.buf.Reset()
+	}
 	return nil
 }
 
@@ -66,15 +107,15 @@ func (h *terminalHandler) WithGroup(name string) slog.Handler {
 
 func (h *terminalHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
 	return &terminalHandler{
-		wr:           h.wr,
-		cfg:          h.cfg,
-		attrs:        append(h.attrs, attrs...),
-		fieldPadding: make(map[string]int),
-		buf:          nil,
+		wr:            h.wr,
+		cfg:           h.cfg,
+		attrs:         slices.Concat(h.attrs, attrs),
+		TerminalState: h.TerminalState,
 	}
 }
 
-// ResetFieldPadding zeroes the field-padding for all attribute pairs.
+// ResetFieldPadding zeroes the field-padding for all attribute pairs,
+// of all handlers that share the same TerminalState.
 func (h *terminalHandler) ResetFieldPadding() {
 	h.mu.Lock()
 	h.fieldPadding = make(map[string]int)
@@ -2,6 +2,7 @@ package log_test
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/protolambda/proto-log/log"
@@ -25,3 +26,61 @@ func TestTerminalHandler(t *testing.T) {
 	assertSubstring(t, got, `foo=1`)
 	assertSubstring(t, got, `bar=2`)
 }
+
+func TestTerminalHandlerMultiLine(t *testing.T) {
+	var buf bytes.Buffer
+	h := log.TerminalHandler(&buf, log.WithExcludeTime(true), log.WithMultiLine(true))
+	logger := log.New(h)
+	logger.Info("query failed\nwith details", "query", "SELECT *\nFROM blocks\n", "err", errors.New("boom"))
+	expected := `INFO  query failed                             query=<2 lines> err=boom
+  | with details
+  | query:
+  |   SELECT *
+  |   FROM blocks
+`
+	assertEqual(t, buf.String(), expected)
+
+	buf.Reset()
+	logger = log.New(log.TerminalHandler(&buf, log.WithExcludeTime(true)))
+	logger.Info("single", "query", "SELECT *\nFROM blocks")
+	assertEqual(t, buf.String(), `INFO  single                                   query="SELECT *\nFROM blocks"`+"\n")
+}
+
+func TestTerminalHandlerSharedPadding(t *testing.T) {
+	var buf bytes.Buffer
+	h := log.TerminalHandler(&buf, log.WithExcludeTime(true), log.WithFieldWidths(map[string]int{"id": 6}))
+	logger := log.New(h)
+	sub := logger.With("sub", true)
+	logger.Info("a", "x", "long-value", "id", 1, "y", 1)
+	sub.Info("b", "x", "short", "id", 2, "y", 2)
+	expected := `INFO  a                                        x=long-value id=1      y=1
+INFO  b                                        sub=true x=short      id=2      y=2
+`
+	assertEqual(t, buf.String(), expected)
+
+	resetter, ok := log.FindHandler[log.FieldPaddingResetter](sub.Handler())
+	assertTrue(t, ok)
+	resetter.ResetFieldPadding()
+	buf.Reset()
+	logger.Info("c", "x", "short", "y", 3)
+	assertEqual(t, buf.String(), "INFO  c                                        x=short y=3\n")
+}
+
+func TestTerminalHandlerSharedState(t *testing.T) {
+	var buf bytes.Buffer
+	state := log.NewTerminalState()
+	a := log.New(log.TerminalHandler(&buf, log.WithExcludeTime(true), log.WithSharedState(state)))
+	b := log.New(log.TerminalHandler(&buf, log.WithExcludeTime(true), log.WithSharedState(state)))
+	a.Info("a", "x", "long-value", "y", 1)
+	b.Info("b", "x", "short", "y", 2)
+	expected := `INFO  a                                        x=long-value y=1
+INFO  b                                        x=short      y=2
+`
+	assertEqual(t, buf.String(), expected)
+
+	// handlers without shared state align their attributes separately
+	buf.Reset()
+	c := log.New(log.TerminalHandler(&buf, log.WithExcludeTime(true)))
+	c.Info("c", "x", "short", "y", 3)
+	assertEqual(t, buf.String(), "INFO  c                                        x=short y=3\n")
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"strings"
 )
 
 type T interface {
@@ -19,34 +20,129 @@ type T interface {
 
 	Error(args ...any)
 	FailNow()
+
+	// Failed reports whether the function has failed.
+	Failed() bool
+
+	// Cleanup registers a function to be called when the test (or subtest) and all its subtests complete.
+	Cleanup(f func())
+}
+
+// helperT is implemented by a T that can mark test helper functions, like testing.TB.
+// Assertions call Helper directly if available, so failures point to the caller of the assertion.
+type helperT interface {
+	Helper()
 }
 
+// TestStreamEnv is the environment variable that, when set to "1" or "true",
+// makes BufferedTestLogger stream all logs like TestLogger does.
+const TestStreamEnv = "PROTOLOG_TEST_STREAM"
+
 // TestLogger creates a TerminalHandler configured for testing.
 // All log-output is written to the T.Output().
-// Color is enabled.
+// Color is auto-detected: set FORCE_COLOR=1 to enable it, since the test output is not a terminal.
 // Source-info is enabled.
-// Crit-level logs will be followed up with a T.FailNow().
+// Crit-level logs will be followed up with a T.FailNow(),
+// unless another crit policy is added with a CritMod, e.g. CritMod(TestCritMarkFailed(t)).
 func TestLogger(t T, mods ...HandlerMod) Logger {
-	var h slog.Handler
+	h, ok := testTerminalHandler(t)
+	if !ok {
+		return nil
+	}
+	for _, m := range mods {
+		h = m(h)
+	}
+	return New(testCritMod(t, h))
+}
+
+// BufferedTestLogger is like TestLogger, but buffers all log records,
+// and only writes them to the T.Output() when the test has failed, at the end of the test,
+// or when the handlers are flushed, see FlushHandlers.
+// This keeps the output of passing tests free of log noise.
+// Use a LevelMod to choose the level of logs to buffer, per test.
+// Set the TestStreamEnv environment variable to stream the logs instead, like TestLogger does.
+// Crit-level logs are handled like in TestLogger.
+func BufferedTestLogger(t T, mods ...HandlerMod) Logger {
+	switch strings.ToLower(os.Getenv(TestStreamEnv)) {
+	case "1", "true":
+		return TestLogger(t, mods...)
+	}
+	out, ok := testTerminalHandler(t)
+	if !ok {
+		return nil
+	}
+	logs := new([]*CapturedRecord)
+	written := 0
+	flush := func() error {
+		writeRecords(out, (*logs)[written:])
+		written = len(*logs)
+		return nil
+	}
+	capt := &CapturingHandler{handler: bufferingHandler{flush: flush}, Logs: logs}
+	t.Cleanup(func() {
+		if t.Failed() {
+			_ = flush()
+		}
+	})
+	var h slog.Handler = capt
+	for _, m := range mods {
+		h = m(h)
+	}
+	return New(testCritMod(t, h))
+}
+
+func testTerminalHandler(t T) (slog.Handler, bool) {
 	wd, err := os.Getwd()
 	if err != nil {
 		t.Error("failed to get work-dir:", err)
 		t.FailNow()
-		return nil
+		return nil, false
 	}
-	h = TerminalHandler(t.Output(),
-		WithColor(true),
+	return TerminalHandler(t.Output(),
+		WithAutoColor(),
 		WithIncludeSource(true),
-		WithSourceRelDir(wd))
-	for _, m := range mods {
-		h = m(h)
+		WithSourceRelDir(wd)), true
+}
+
+// testCritMod adds the TestCritFailNow policy to h, if h does not have a crit policy yet.
+func testCritMod(t T, h slog.Handler) slog.Handler {
+	if _, ok := FindHandler[*CritHandler](h); ok {
+		return h
+	}
+	return CritMod(TestCritFailNow(t))(h)
+}
+
+// TestCritFailNow fails the test with T.FailNow after crit-level logs.
+func TestCritFailNow(t T) CritPolicy {
+	return func(ctx context.Context, r slog.Record, inner slog.Handler) {
+		t.Error("CRIT-level log: " + r.Message)
+		t.FailNow()
 	}
-	// FailNow after Crit-level logs
-	h = PostProcessMod(func(ctx context.Context, r slog.Record) {
-		if r.Level >= LevelCrit {
-			t.Error("CRIT-level log")
-			t.FailNow()
-		}
-	})(h)
-	return New(h)
 }
+
+// TestCritMarkFailed marks the test as failed after crit-level logs, and continues the test.
+func TestCritMarkFailed(t T) CritPolicy {
+	return func(ctx context.Context, r slog.Record, inner slog.Handler) {
+		t.Error("CRIT-level log: " + r.Message)
+	}
+}
+
+// TestCritAllow allows crit-level logs, e.g. when the test expects them.
+func TestCritAllow() CritPolicy {
+	return func(ctx context.Context, r slog.Record, inner slog.Handler) {}
+}
+
+// bufferingHandler accepts all records, for a CapturingHandler to buffer them.
+// Flushing writes the records that were buffered since the last flush, e.g. before a crit policy exits the process.
+type bufferingHandler struct {
+	flush func() error
+}
+
+var _ Flusher = bufferingHandler{}
+
+func (h bufferingHandler) Flush() error { return h.flush() }
+
+func (bufferingHandler) Enabled(context.Context, slog.Level) bool  { return true }
+func (bufferingHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h bufferingHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h bufferingHandler) WithGroup(string) slog.Handler           { return h }
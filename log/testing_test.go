@@ -1,6 +1,11 @@
 package log
 
-import "testing"
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
 
 func TestNewTestLogger(t *testing.T) {
 	logger := TestLogger(t)
@@ -9,3 +14,76 @@ func TestNewTestLogger(t *testing.T) {
 	subLogger.Debug("Testing sub-logger")
 	//subLogger.Crit("example") // To make the test fail
 }
+
+// fakeT runs cleanup functions on demand, and captures the test output.
+type fakeT struct {
+	out      bytes.Buffer
+	failed   bool
+	cleanups []func()
+}
+
+func (f *fakeT) Output() io.Writer { return &f.out }
+func (f *fakeT) Error(args ...any) { f.failed = true }
+func (f *fakeT) FailNow()          { f.failed = true }
+func (f *fakeT) Failed() bool      { return f.failed }
+func (f *fakeT) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeT) runCleanup() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestBufferedTestLogger(t *testing.T) {
+	t.Setenv(TestStreamEnv, "")
+	t.Run("passing", func(t *testing.T) {
+		ft := &fakeT{}
+		logger := BufferedTestLogger(ft)
+		logger.Info("hidden")
+		ft.runCleanup()
+		if ft.out.Len() != 0 {
+			t.Fatalf("expected no output, got %q", ft.out.String())
+		}
+	})
+	t.Run("failing", func(t *testing.T) {
+		ft := &fakeT{}
+		logger := BufferedTestLogger(ft, LevelMod(LevelInfo))
+		logger.Debug("filtered")
+		logger.With("a", 1).Info("shown")
+		if ft.out.Len() != 0 {
+			t.Fatal("expected buffering")
+		}
+		ft.failed = true
+		ft.runCleanup()
+		got := ft.out.String()
+		if !strings.Contains(got, "shown") || !strings.Contains(got, "a=1") || strings.Contains(got, "filtered") {
+			t.Fatalf("unexpected output: %q", got)
+		}
+	})
+	t.Run("flushed", func(t *testing.T) {
+		ft := &fakeT{}
+		logger := BufferedTestLogger(ft)
+		logger.Info("before flush")
+		if err := FlushHandlers(logger.With("a", 1).Handler()); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(ft.out.String(), "before flush") {
+			t.Fatalf("expected flushed output, got %q", ft.out.String())
+		}
+		logger.Info("after flush")
+		ft.failed = true
+		ft.runCleanup()
+		got := ft.out.String()
+		if strings.Count(got, "before flush") != 1 || !strings.Contains(got, "after flush") {
+			t.Fatalf("expected remaining records to be written once, got %q", got)
+		}
+	})
+	t.Run("streaming", func(t *testing.T) {
+		t.Setenv(TestStreamEnv, "1")
+		ft := &fakeT{}
+		logger := BufferedTestLogger(ft)
+		logger.Info("streamed")
+		if !strings.Contains(ft.out.String(), "streamed") {
+			t.Fatal("expected streamed output")
+		}
+	})
+}
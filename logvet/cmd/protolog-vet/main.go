@@ -0,0 +1,17 @@
+// Command protolog-vet checks proto-log and slog call sites, see the logvet package.
+// Install it from a clone of the repository:
+//
+//	cd proto-log/logvet && go install ./cmd/protolog-vet
+//
+//	go vet -vettool=$(which protolog-vet) -protologvet.keycase=snake ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/protolambda/proto-log/logvet"
+)
+
+func main() {
+	unitchecker.Main(logvet.Analyzer)
+}
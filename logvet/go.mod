@@ -0,0 +1,16 @@
+module github.com/protolambda/proto-log/logvet
+
+go 1.25.0
+
+require (
+	github.com/protolambda/proto-log v0.0.0
+	golang.org/x/tools v0.47.0
+)
+
+require (
+	golang.org/x/mod v0.37.0 // indirect
+	golang.org/x/sync v0.21.0 // indirect
+)
+
+// Built from a checkout of the repository, against the proto-log module next to it.
+replace github.com/protolambda/proto-log => ../
@@ -0,0 +1,66 @@
+// Package logvet provides an analysis.Analyzer for proto-log and slog call sites,
+// built on the checks of the logcheck package.
+//
+// It is a separate module, so the proto-log module itself stays free of dependencies.
+// It builds against the proto-log module of the same checkout, so install it from a clone
+// of the repository, and run it with go vet:
+//
+//	cd proto-log/logvet && go install ./cmd/protolog-vet
+//	go vet -vettool=$(which protolog-vet) ./...
+package logvet
+
+import (
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/protolambda/proto-log/log/logcheck"
+)
+
+const doc = `check proto-log and slog call sites
+
+The protologvet analyzer reports logging calls of the proto-log Logger, ExtendedSLogLogger
+and SLogLogger interfaces, and of log/slog, with:
+  - mismatched key/value arguments, that would be logged as "!BADKEY"
+  - non-constant keys
+  - keys that do not follow the naming convention (-keycase), or that are spelled inconsistently
+  - calls like Info, while a context.Context is in scope, where InfoContext could be used`
+
+// Analyzer checks logging calls. See the -keycase and -nocontext flags to configure it.
+var Analyzer = &analysis.Analyzer{
+	Name: "protologvet",
+	Doc:  doc,
+	URL:  "https://pkg.go.dev/github.com/protolambda/proto-log/logvet",
+	Run:  run,
+}
+
+var config logcheck.Config
+
+func init() {
+	Analyzer.Flags.TextVar(&config.KeyCase, "keycase", logcheck.KeyCaseAny,
+		"naming convention of attribute keys: any, snake, camel, kebab or lower")
+	Analyzer.Flags.BoolVar(&config.NoContextCheck, "nocontext", false,
+		"disable the check for calls like Info where InfoContext could be used")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, d := range config.Check(pass.Pkg, pass.TypesInfo, pass.Files) {
+		pass.Report(diagnostic(d))
+	}
+	return nil, nil
+}
+
+func diagnostic(d logcheck.Diagnostic) analysis.Diagnostic {
+	out := analysis.Diagnostic{
+		Pos:      d.Pos,
+		End:      d.End,
+		Category: d.Category,
+		Message:  d.Message,
+	}
+	for _, fix := range d.Fixes {
+		sf := analysis.SuggestedFix{Message: fix.Message}
+		for _, e := range fix.Edits {
+			sf.TextEdits = append(sf.TextEdits, analysis.TextEdit{Pos: e.Pos, End: e.End, NewText: e.NewText})
+		}
+		out.SuggestedFixes = append(out.SuggestedFixes, sf)
+	}
+	return out
+}
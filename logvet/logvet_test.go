@@ -0,0 +1,13 @@
+package logvet_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/protolambda/proto-log/logvet"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), logvet.Analyzer, "example")
+}
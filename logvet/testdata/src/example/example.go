@@ -0,0 +1,28 @@
+package example
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/protolambda/proto-log/log"
+)
+
+func arity(logger log.Logger, key string, blockNum uint64) {
+	logger.Info("ok", "a", 1, slog.Int("b", 2))
+	logger.Info("odd", "a", 1, "b") // want `key "b" is missing a value`
+	logger.Info("no key", blockNum) // want `expected a string key or slog.Attr, got blockNum of type uint64`
+	logger.Info("dynamic", key, 1)  // want `key key is not a constant`
+	logger.With("peer", "abc", "x") // want `key "x" is missing a value`
+	logger.Infof("format %d", 123)
+}
+
+func casing(logger log.ExtendedSLogLogger) {
+	logger.Trace("first", "blockNum", 1)
+	logger.Trace("second", "block_num", 2) // want `key "block_num" is inconsistent with key "blockNum"`
+}
+
+func withContext(ctx context.Context, logger log.SLogLogger, sl *slog.Logger) {
+	logger.Info("hello") // want `Info is called with ctx in scope, use InfoContext instead`
+	sl.Debug("hello")    // want `Debug is called with ctx in scope, use DebugContext instead`
+	slog.Warn("hello")   // want `Warn is called with ctx in scope, use WarnContext instead`
+}
@@ -0,0 +1,27 @@
+// Package log is a stub of the proto-log log package, with the methods that are relevant to the analyzer.
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+type SLogLogger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	DebugContext(ctx context.Context, msg string, args ...any)
+	InfoContext(ctx context.Context, msg string, args ...any)
+	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+type ExtendedSLogLogger interface {
+	SLogLogger
+	Trace(msg string, args ...any)
+	TraceContext(ctx context.Context, msg string, args ...any)
+}
+
+type Logger interface {
+	ExtendedSLogLogger
+	With(args ...any) Logger
+	Infof(format string, args ...any)
+}